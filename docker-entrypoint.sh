@@ -9,13 +9,18 @@ if [ x"${GITHUB_ACTIONS}" = xtrue ]; then
         INPUT_DESTINATION="$(realpath -m "${INPUT_DESTINATION}")"
     fi
 
+    if [ -n "${INPUT_STATE}" ]; then
+        INPUT_STATE="$(realpath -m "${INPUT_STATE}")"
+    fi
+
     if [ -n "${INPUT_SOURCE}" ]; then
         cd "${INPUT_SOURCE}"
     fi
 
     exec /bin/website \
         ${INPUT_CONFIGURATION+-c "${INPUT_CONFIGURATION}"} \
-        ${INPUT_DESTINATION+-d "${INPUT_DESTINATION}"}
+        ${INPUT_DESTINATION+-d "${INPUT_DESTINATION}"} \
+        ${INPUT_STATE+-s "${INPUT_STATE}"}
 fi
 
 exec /bin/website "${@}"
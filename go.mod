@@ -10,6 +10,7 @@ require (
 	github.com/mangoumbrella/goldmark-figure v1.4.0
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/tdewolff/minify/v2 v2.24.11
+	github.com/tdewolff/parse/v2 v2.8.11
 	github.com/ulikunitz/xz v0.5.15
 	github.com/yuin/goldmark v1.8.2
 	github.com/yuin/goldmark-emoji v1.0.6
@@ -21,6 +22,5 @@ require (
 
 require (
 	github.com/dlclark/regexp2 v1.11.5 // indirect
-	github.com/tdewolff/parse/v2 v2.8.11 // indirect
 	golang.org/x/text v0.35.0 // indirect
 )
@@ -0,0 +1,160 @@
+package badge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+
+	"rafaelmartins.com/p/website/internal/hexcolor"
+	"rafaelmartins.com/p/website/internal/opengraph"
+)
+
+const (
+	defaultFontFile   = "fonts/ttf/AtkinsonHyperlegibleNext-Regular.ttf"
+	defaultFontFamily = "Atkinson Hyperlegible Next,Verdana,DejaVu Sans,sans-serif"
+	defaultFontSize   = 11
+	padding           = 6
+)
+
+// named colors are compatible with shields.io
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"grey":        "#555",
+}
+
+type Config struct {
+	Font       opengraph.FontConfig `yaml:"font"`
+	FontFamily *string              `yaml:"font-family"`
+	FontSize   *float64             `yaml:"font-size"`
+}
+
+type Renderer struct {
+	family   string
+	size     float64
+	measurer interface {
+		Measure(text string) (float64, error)
+	}
+}
+
+func NewRenderer(config *Config) *Renderer {
+	if config == nil {
+		config = &Config{}
+	}
+
+	size := float64(defaultFontSize)
+	if config.FontSize != nil {
+		size = *config.FontSize
+	}
+
+	rv := &Renderer{
+		family:   defaultFontFamily,
+		size:     size,
+		measurer: opengraph.NewTextMeasurer(&config.Font, defaultFontFile, size),
+	}
+	if config.FontFamily != nil {
+		rv.family = *config.FontFamily
+	}
+	return rv
+}
+
+func getColor(c string) (string, error) {
+	if c == "" {
+		return colors["blue"], nil
+	}
+	if v, ok := colors[c]; ok {
+		return v, nil
+	}
+	if _, err := hexcolor.ToRGBA(c); err != nil {
+		return "", err
+	}
+	return c, nil
+}
+
+func (r *Renderer) width(text string) (int, error) {
+	w, err := r.measurer.Measure(text)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Ceil(w)), nil
+}
+
+// Render renders a flat badge, similar to the ones generated by shields.io.
+// the text length is forced to the measured width, to keep the layout
+// consistent when browsers render it with a fallback font.
+func (r *Renderer) Render(label string, value string, color string) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("badge: renderer not initialized")
+	}
+	if value == "" {
+		return nil, errors.New("badge: empty value")
+	}
+
+	c, err := getColor(color)
+	if err != nil {
+		return nil, fmt.Errorf("badge: %w", err)
+	}
+
+	lw := 0
+	ltw := 0
+	if label != "" {
+		ltw, err = r.width(label)
+		if err != nil {
+			return nil, err
+		}
+		lw = ltw + 2*padding
+	}
+
+	vtw, err := r.width(value)
+	if err != nil {
+		return nil, err
+	}
+	vw := vtw + 2*padding
+	w := lw + vw
+
+	title := value
+	if label != "" {
+		title = label + ": " + value
+	}
+
+	family := html.EscapeString(r.family)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, w, html.EscapeString(title))
+	fmt.Fprintf(buf, `<title>%s</title>`, html.EscapeString(title))
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, w)
+	buf.WriteString(`<g clip-path="url(#r)">`)
+	if label != "" {
+		fmt.Fprintf(buf, `<rect width="%d" height="20" fill="#555"/>`, lw)
+	}
+	fmt.Fprintf(buf, `<rect x="%d" width="%d" height="20" fill="%s"/>`, lw, vw, c)
+	fmt.Fprintf(buf, `<rect width="%d" height="20" fill="url(#s)"/>`, w)
+	buf.WriteString(`</g>`)
+	fmt.Fprintf(buf, `<g fill="#fff" text-anchor="middle" font-family="%s" font-size="%s">`, family, strconv.FormatFloat(r.size, 'f', -1, 64))
+	for _, t := range []struct {
+		x     int
+		width int
+		text  string
+	}{
+		{lw / 2, ltw, label},
+		{lw + vw/2, vtw, value},
+	} {
+		if t.text == "" {
+			continue
+		}
+		fmt.Fprintf(buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3" textLength="%d" lengthAdjust="spacingAndGlyphs">%s</text>`, t.x, t.width, html.EscapeString(t.text))
+		fmt.Fprintf(buf, `<text x="%d" y="14" textLength="%d" lengthAdjust="spacingAndGlyphs">%s</text>`, t.x, t.width, html.EscapeString(t.text))
+	}
+	buf.WriteString(`</g></svg>`)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
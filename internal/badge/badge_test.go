@@ -0,0 +1,101 @@
+package badge
+
+import (
+	"strings"
+	"testing"
+)
+
+type testMeasurer struct{}
+
+func (testMeasurer) Measure(text string) (float64, error) {
+	return float64(len(text)) * 6.5, nil
+}
+
+func newTestRenderer() *Renderer {
+	return &Renderer{
+		family:   "sans-serif",
+		size:     11,
+		measurer: testMeasurer{},
+	}
+}
+
+func TestGetColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "#007ec6", false},
+		{"green", "#97ca00", false},
+		{"#ff8040", "#ff8040", false},
+		{"#abc", "#abc", false},
+		{"ff8040", "", true},
+		{"purple", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := getColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := newTestRenderer()
+
+	b, err := r.Render("version", "v1.0", "green")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	svg := string(b)
+
+	// "version" is 46 pixels wide (45.5 rounded up), "v1.0" is 26 pixels wide
+	for _, want := range []string{
+		`width="96"`,
+		`aria-label="version: v1.0"`,
+		`<rect width="58" height="20" fill="#555"/>`,
+		`<rect x="58" width="38" height="20" fill="#97ca00"/>`,
+		`<text x="29" y="14" textLength="46" lengthAdjust="spacingAndGlyphs">version</text>`,
+		`<text x="77" y="14" textLength="26" lengthAdjust="spacingAndGlyphs">v1.0</text>`,
+		`font-size="11"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("missing %q in %s", want, svg)
+		}
+	}
+}
+
+func TestRenderEscape(t *testing.T) {
+	b, err := newTestRenderer().Render("a<b", "c&d", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if svg := string(b); strings.Contains(svg, "a<b") || !strings.Contains(svg, "a&lt;b") || !strings.Contains(svg, "c&amp;d") {
+		t.Errorf("text not escaped: %s", svg)
+	}
+}
+
+func TestRenderNoLabel(t *testing.T) {
+	b, err := newTestRenderer().Render("", "passing", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if svg := string(b); strings.Contains(svg, `fill="#555"`) || !strings.Contains(svg, `<title>passing</title>`) {
+		t.Errorf("unexpected label: %s", svg)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := newTestRenderer().Render("label", "", ""); err == nil {
+		t.Error("expected error for empty value")
+	}
+	if _, err := newTestRenderer().Render("label", "value", "bad"); err == nil {
+		t.Error("expected error for bad color")
+	}
+}
@@ -2,6 +2,7 @@ package cdocs
 
 import (
 	"fmt"
+	"html/template"
 	"io"
 	"slices"
 	"strings"
@@ -248,7 +249,7 @@ func (t *TemplateCtx) Dump(w io.Writer) {
 type HeaderCtx struct {
 	ID          string
 	Name        string
-	Description string
+	Description template.HTML
 
 	Includes []string
 
@@ -264,7 +265,7 @@ type HeaderCtx struct {
 type SectionCtx struct {
 	ID          string
 	Name        string
-	Description string
+	Description template.HTML
 
 	Defines       []*EntryCtx
 	Structs       []*EntryCtx
@@ -277,8 +278,8 @@ type EntryCtx struct {
 	ID          string
 	Type        string
 	Name        string
-	Proto       string
-	Description string
+	Proto       template.HTML
+	Description template.HTML
 	Link        string
 }
 
@@ -307,7 +308,7 @@ func NewTemplateCtx(headers []*TemplateCtxHeader) (*TemplateCtx, error) {
 
 		section := (*SectionCtx)(nil)
 		pendingSection := (*SectionCtx)(nil)
-		pendingDescription := ""
+		var pendingDescription template.HTML
 
 		for _, entry := range hdr.Header.Entries {
 			if c := entry.Comment; c != nil {
@@ -450,20 +451,20 @@ func NewTemplateCtx(headers []*TemplateCtxHeader) (*TemplateCtx, error) {
 				}
 
 				if isFile {
-					hctx.Description = description
+					hctx.Description = template.HTML(description)
 					continue
 				}
 
 				if isSection {
 					if pendingSection != nil {
-						pendingSection.Description = description
+						pendingSection.Description = template.HTML(description)
 					} else if section != nil {
-						section.Description = description
+						section.Description = template.HTML(description)
 					}
 					continue
 				}
 
-				pendingDescription = description
+				pendingDescription = template.HTML(description)
 				continue
 			}
 
@@ -2,6 +2,7 @@ package cdocs
 
 import (
 	"bytes"
+	"html/template"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters/html"
@@ -18,7 +19,7 @@ var (
 	chStyle = styles.Get("github")
 )
 
-func highlight(code string) (string, error) {
+func highlight(code string) (template.HTML, error) {
 	iter, err := chLexer.Tokenise(nil, code)
 	if err != nil {
 		return "", err
@@ -29,5 +30,5 @@ func highlight(code string) (string, error) {
 	if err := chFormatter.Format(&b, chStyle, iter); err != nil {
 		return "", err
 	}
-	return b.String(), nil
+	return template.HTML(b.String()), nil
 }
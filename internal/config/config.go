@@ -1,32 +1,68 @@
 package config
 
 import (
+	"html/template"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"go.yaml.in/yaml/v3"
+	"rafaelmartins.com/p/website/internal/badge"
 	"rafaelmartins.com/p/website/internal/opengraph"
 )
 
 type Config struct {
-	Title   string `yaml:"title"`
-	Footer  string `yaml:"footer"`
-	URL     string `yaml:"url"`
-	License string `yaml:"license"`
+	Title   string        `yaml:"title"`
+	Footer  template.HTML `yaml:"footer"` // trusted html, not escaped
+	URL     string        `yaml:"url"`
+	License string        `yaml:"license"`
 
 	Author struct {
 		Name  string `yaml:"name"`
 		Email string `yaml:"email"`
 	} `yaml:"author"`
 
+	Authors map[string]*struct {
+		Name   string `yaml:"name"`
+		Email  string `yaml:"email"`
+		Bio    string `yaml:"bio"`
+		Avatar string `yaml:"avatar"`
+		Links  []*struct {
+			URL   string `yaml:"url"`
+			Label string `yaml:"label"`
+			Icon  string `yaml:"icon"`
+		} `yaml:"links"`
+	} `yaml:"authors"`
+
+	AuthorPages struct {
+		BaseDestination  string            `yaml:"base-destination"`
+		PostsPerPage     int               `yaml:"posts-per-page"`
+		PostsPerPageAtom int               `yaml:"posts-per-page-atom"`
+		Template         string            `yaml:"template"`
+		TemplateAtom     string            `yaml:"template-atom"`
+		TemplateCtx      map[string]any    `yaml:"template-context"`
+		WithSidebar      bool              `yaml:"with-sidebar"`
+		OpenGraph        *opengraph.Config `yaml:"opengraph"`
+	} `yaml:"author-pages"`
+
 	Template         *string        `yaml:"template"`
 	TemplatePartials []string       `yaml:"template-partials"`
 	TemplateCtx      map[string]any `yaml:"template-context"`
 
+	Theme struct {
+		Directory       string   `yaml:"directory"`
+		Names           []string `yaml:"names"`
+		ThemesDirectory string   `yaml:"themes-directory"`
+	} `yaml:"theme"`
+
 	Search bool `yaml:"search"`
 
 	OpenGraphImageGen *opengraph.ImageGenConfig `yaml:"opengraph-image-gen"`
 
+	Badges *badge.Config `yaml:"badges"`
+
 	Assets struct {
 		BaseDestination string `yaml:"base-destination"`
 		Npm             []*struct {
@@ -51,6 +87,11 @@ type Config struct {
 		} `yaml:"dropdown"`
 	} `yaml:"menu"`
 
+	Redirects []*struct {
+		From string `yaml:"from"`
+		To   string `yaml:"to"`
+	} `yaml:"redirects"`
+
 	SocialLinks []*struct {
 		URL   string `yaml:"url"`
 		Label string `yaml:"label"`
@@ -59,14 +100,16 @@ type Config struct {
 
 	Projects []*struct {
 		Repositories []*struct {
-			Owner    string `yaml:"owner"`
-			Repo     string `yaml:"repo"`
-			Licenses []struct {
+			Owner        string `yaml:"owner"`
+			Repo         string `yaml:"repo"`
+			Subdirectory string `yaml:"subdirectory"`
+			Licenses     []struct {
 				SpdxId string `yaml:"spdx-id"`
 				Title  string `yaml:"title"`
 			} `yaml:"licenses"`
 			RollingTag *string  `yaml:"rolling-tag"`
 			Toc        bool     `yaml:"toc"`
+			UnsafeHTML bool     `yaml:"unsafe-html"`
 			Files      []string `yaml:"files"`
 			CDocs      struct {
 				Destination   string            `yaml:"destination"`
@@ -75,6 +118,45 @@ type Config struct {
 				Template      string            `yaml:"template"`
 				OpenGraph     *opengraph.Config `yaml:"opengraph"`
 			} `yaml:"c-docs"`
+			Releases struct {
+				Destination  string            `yaml:"destination"`
+				PerPage      int               `yaml:"per-page"`
+				PerPageAtom  int               `yaml:"per-page-atom"`
+				Template     string            `yaml:"template"`
+				TemplateAtom string            `yaml:"template-atom"`
+				OpenGraph    *opengraph.Config `yaml:"opengraph"`
+			} `yaml:"releases"`
+			Source struct {
+				Destination string            `yaml:"destination"`
+				Patterns    []string          `yaml:"patterns"`
+				Template    string            `yaml:"template"`
+				OpenGraph   *opengraph.Config `yaml:"opengraph"`
+			} `yaml:"source"`
+			Issues struct {
+				Destination string            `yaml:"destination"`
+				Milestones  bool              `yaml:"milestones"`
+				Labels      []string          `yaml:"labels"`
+				Template    string            `yaml:"template"`
+				OpenGraph   *opengraph.Config `yaml:"opengraph"`
+			} `yaml:"issues"`
+			Downloads struct {
+				Enabled       bool              `yaml:"enabled"`
+				Destination   string            `yaml:"destination"`
+				Releases      int               `yaml:"releases"`
+				MirrorPattern string            `yaml:"mirror-pattern"`
+				Template      string            `yaml:"template"`
+				OpenGraph     *opengraph.Config `yaml:"opengraph"`
+			} `yaml:"downloads"`
+			Badges struct {
+				Enabled     bool   `yaml:"enabled"`
+				Destination string `yaml:"destination"`
+				Custom      []*struct {
+					Name  string `yaml:"name"`
+					Label string `yaml:"label"`
+					Value string `yaml:"value"`
+					Color string `yaml:"color"`
+				} `yaml:"custom"`
+			} `yaml:"badges"`
 			Dfu struct {
 				Destination          string `yaml:"destination"`
 				ReleaseAssetsPattern string `yaml:"release-assets-pattern"`
@@ -88,16 +170,40 @@ type Config struct {
 				} `yaml:"pcb-render"`
 			} `yaml:"kicad"`
 			Go struct {
-				Import string `yaml:"import"`
-				Repo   string `yaml:"repo"`
+				Import   string   `yaml:"import"`
+				Repo     string   `yaml:"repo"`
+				Packages []string `yaml:"packages"`
+				Docs     struct {
+					Enabled     bool              `yaml:"enabled"`
+					Destination string            `yaml:"destination"`
+					Template    string            `yaml:"template"`
+					Internal    bool              `yaml:"internal"`
+					GOOS        string            `yaml:"goos"`
+					GOARCH      string            `yaml:"goarch"`
+					OpenGraph   *opengraph.Config `yaml:"opengraph"`
+				} `yaml:"docs"`
 			} `yaml:"go"`
 			OpenGraph *opengraph.Config `yaml:"opengraph"`
 			Immutable *bool             `yaml:"immutable"`
+			TTL       *time.Duration    `yaml:"ttl"`
 		} `yaml:"repositories"`
-		BaseDestination string `yaml:"base-destination"`
-		Template        string `yaml:"template"`
+		Title           string        `yaml:"title"`
+		BaseDestination string        `yaml:"base-destination"`
+		Template        string        `yaml:"template"`
+		TTL             time.Duration `yaml:"ttl"`
 	} `yaml:"projects"`
 
+	ProjectsOverview *struct {
+		Title           string            `yaml:"title"`
+		Description     string            `yaml:"description"`
+		Group           bool              `yaml:"group"`
+		Sort            string            `yaml:"sort"`
+		BaseDestination string            `yaml:"base-destination"`
+		Template        string            `yaml:"template"`
+		WithSidebar     bool              `yaml:"with-sidebar"`
+		OpenGraph       *opengraph.Config `yaml:"opengraph"`
+	} `yaml:"projects-overview"`
+
 	DfuFlasher *struct {
 		Title           string            `yaml:"title"`
 		Description     string            `yaml:"description"`
@@ -107,6 +213,17 @@ type Config struct {
 		OpenGraph       *opengraph.Config `yaml:"opengraph"`
 	} `yaml:"dfu-flasher"`
 
+	GoVanity *struct {
+		Title           string            `yaml:"title"`
+		Description     string            `yaml:"description"`
+		BaseDestination string            `yaml:"base-destination"`
+		Template        string            `yaml:"template"`
+		TemplateImport  string            `yaml:"template-import"`
+		WithSidebar     bool              `yaml:"with-sidebar"`
+		OpenGraph       *opengraph.Config `yaml:"opengraph"`
+		Modules         []*GoVanityModule `yaml:"modules"`
+	} `yaml:"go-vanity"`
+
 	Pages []*struct {
 		Sources []*struct {
 			Title       string            `yaml:"title"`
@@ -117,6 +234,7 @@ type Config struct {
 			Toc         *bool             `yaml:"toc"`
 			OpenGraph   *opengraph.Config `yaml:"opengraph"`
 		} `yaml:"sources"`
+		SourceDir         string         `yaml:"source-dir"`
 		Toc               bool           `yaml:"toc"`
 		ExtraDependencies []string       `yaml:"extra-dependencies"`
 		PrettyURL         *bool          `yaml:"pretty-url"`
@@ -135,8 +253,10 @@ type Config struct {
 		BaseDestination    string            `yaml:"base-destination"`
 		TemplateAtom       string            `yaml:"template-atom"`
 		TemplatePagination string            `yaml:"template-pagination"`
+		TemplateArchive    string            `yaml:"template-archive"`
 		TemplateCtx        map[string]any    `yaml:"template-context"`
 		WithSidebar        bool              `yaml:"with-sidebar"`
+		Archive            bool              `yaml:"archive"`
 		OpenGraph          *opengraph.Config `yaml:"opengraph"`
 
 		Groups []*struct {
@@ -148,11 +268,15 @@ type Config struct {
 			PostsPerPageAtom   int               `yaml:"posts-per-page-atom"`
 			SortReverse        *bool             `yaml:"sort-reverse"`
 			BaseDestination    string            `yaml:"base-destination"`
+			Permalink          string            `yaml:"permalink"`
 			Template           string            `yaml:"template"`
+			TemplateSeries     string            `yaml:"template-series"`
 			TemplateAtom       string            `yaml:"template-atom"`
 			TemplatePagination string            `yaml:"template-pagination"`
+			TemplateArchive    string            `yaml:"template-archive"`
 			TemplateCtx        map[string]any    `yaml:"template-context"`
 			WithSidebar        bool              `yaml:"with-sidebar"`
+			Archive            bool              `yaml:"archive"`
 			OpenGraph          *opengraph.Config `yaml:"opengraph"`
 		} `yaml:"groups"`
 	} `yaml:"posts"`
@@ -176,6 +300,42 @@ type Config struct {
 	ts   time.Time
 }
 
+type GoVanityModule struct {
+	Import string `yaml:"import"`
+	VCS    string `yaml:"vcs"`
+	Repo   string `yaml:"repo"`
+	Source *struct {
+		URL     string `yaml:"url"`
+		DirURL  string `yaml:"dir-url"`
+		FileURL string `yaml:"file-url"`
+	} `yaml:"source"`
+	Redirect string   `yaml:"redirect"`
+	Packages []string `yaml:"packages"`
+}
+
+func (m *GoVanityModule) GetVCS() string {
+	if m.VCS == "" {
+		return "git"
+	}
+	return m.VCS
+}
+
+func (m *GoVanityModule) GetSource() (string, string, string) {
+	if m.Source != nil {
+		return m.Source.URL, m.Source.DirURL, m.Source.FileURL
+	}
+
+	// we only know how to guess the source urls of the most common forges
+	u := strings.TrimSuffix(m.Repo, ".git")
+	switch {
+	case strings.HasPrefix(u, "https://github.com/"):
+		return u, u + "/tree/HEAD{/dir}", u + "/blob/HEAD{/dir}/{file}#L{line}"
+	case strings.HasPrefix(u, "https://gitlab.com/"):
+		return u, u + "/-/tree/HEAD{/dir}", u + "/-/blob/HEAD{/dir}/{file}#L{line}"
+	}
+	return "", "", ""
+}
+
 func New(file string) (*Config, error) {
 	f, err := os.Open(file)
 	if err != nil {
@@ -201,6 +361,34 @@ func New(file string) (*Config, error) {
 	return rv, nil
 }
 
+// GetThemeDirectories returns the theme directories, in lookup order: the
+// site-local directory, then the named themes.
+func (c *Config) GetThemeDirectories() []string {
+	rv := []string{}
+	if c.Theme.Directory != "" {
+		rv = append(rv, c.Theme.Directory)
+	}
+
+	themesDir := c.Theme.ThemesDirectory
+	if themesDir == "" {
+		themesDir = "themes"
+	}
+	for _, name := range c.Theme.Names {
+		rv = append(rv, filepath.Join(themesDir, name))
+	}
+	return rv
+}
+
+// GetAuthorBaseDestination returns the base destination of the profile page
+// and feed of an author from the registry.
+func (c *Config) GetAuthorBaseDestination(id string) string {
+	base := c.AuthorPages.BaseDestination
+	if base == "" {
+		base = "authors"
+	}
+	return path.Join(base, id)
+}
+
 func (c *Config) GetTimeStamp() (time.Time, error) {
 	st, err := os.Stat(c.file)
 	if err != nil {
@@ -3,6 +3,8 @@ package content
 import (
 	"fmt"
 	"io"
+	"slices"
+	"sync"
 	"time"
 
 	"rafaelmartins.com/p/website/internal/frontmatter"
@@ -68,7 +70,38 @@ func OpenAsset(f string, a string) (string, io.ReadCloser, error) {
 	return p.OpenAsset(f, a)
 }
 
+type metadataEntry struct {
+	ts   []time.Time
+	meta *frontmatter.FrontMatter
+}
+
+var (
+	metadata  = map[string]*metadataEntry{}
+	metadataM sync.Mutex
+)
+
+// GetMetadata returns the frontmatter of the source. it is cached until the
+// source changes, callers must not modify it.
 func GetMetadata(f string) (*frontmatter.FrontMatter, error) {
+	ts, err := GetTimeStamps(f)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataM.Lock()
+	defer metadataM.Unlock()
+
+	if e, ok := metadata[f]; ok && slices.EqualFunc(e.ts, ts, time.Time.Equal) {
+		return e.meta, nil
+	}
+
 	md, _, _, err := Render(f, "", nil)
-	return md, err
+	if err != nil {
+		return nil, err
+	}
+	metadata[f] = &metadataEntry{
+		ts:   ts,
+		meta: md,
+	}
+	return md, nil
 }
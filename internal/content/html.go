@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"rafaelmartins.com/p/website/internal/frontmatter"
+	"rafaelmartins.com/p/website/internal/sanitizer"
 )
 
 type html struct{}
@@ -33,6 +34,9 @@ func (*html) Render(f string, baseurl string, withToc *bool) (*frontmatter.Front
 	if err != nil {
 		return nil, "", "", err
 	}
+	if meta.Sanitize {
+		return meta, "", sanitizer.Default.Sanitize(f, string(src)), nil
+	}
 	return meta, "", string(src), nil
 }
 
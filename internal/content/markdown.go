@@ -43,6 +43,9 @@ func (*mkd) Render(f string, baseurl string, withToc *bool) (*frontmatter.FrontM
 
 	pc := parser.NewContext()
 	pc.Set(markdown.PcTocEnable, withToc)
+	if meta.Sanitize {
+		pc.Set(markdown.PcSanitize, f)
+	}
 	t, m, err := markdown.Render(gmMarkdown, src, pc)
 	if err != nil {
 		return nil, "", "", err
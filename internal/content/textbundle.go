@@ -53,7 +53,7 @@ func (*tbExtension) Transform(node *ast.Document, reader text.Reader, pc parser.
 	})
 }
 
-func tbRender(r io.Reader, baseurl string, withToc *bool) (*frontmatter.FrontMatter, string, string, error) {
+func tbRender(f string, r io.Reader, baseurl string, withToc *bool) (*frontmatter.FrontMatter, string, string, error) {
 	src, err := io.ReadAll(r)
 	if err != nil {
 		return nil, "", "", err
@@ -70,6 +70,9 @@ func tbRender(r io.Reader, baseurl string, withToc *bool) (*frontmatter.FrontMat
 	pc := parser.NewContext()
 	pc.Set(pcBaseUrl, baseurl)
 	pc.Set(markdown.PcTocEnable, withToc)
+	if meta.Sanitize {
+		pc.Set(markdown.PcSanitize, f)
+	}
 	t, rendered, err := markdown.Render(gmTextBundle, src, pc)
 	if err != nil {
 		return nil, "", "", err
@@ -149,7 +152,7 @@ func (tb *textBundle) Render(f string, baseurl string, withToc *bool) (*frontmat
 	}
 	defer fp.Close()
 
-	return tbRender(fp, baseurl, withToc)
+	return tbRender(f, fp, baseurl, withToc)
 }
 
 func (tb *textBundle) GetTimeStamps(f string) ([]time.Time, error) {
@@ -73,7 +73,7 @@ func (*textPack) Render(f string, baseurl string, withToc *bool) (*frontmatter.F
 	}
 	defer fp.Close()
 
-	return tbRender(fp, baseurl, withToc)
+	return tbRender(f, fp, baseurl, withToc)
 }
 
 func (*textPack) GetTimeStamps(f string) ([]time.Time, error) {
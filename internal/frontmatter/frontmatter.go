@@ -35,18 +35,35 @@ func (d *FrontMatterDate) UnmarshalYAML(node *yaml.Node) error {
 type FrontMatter struct {
 	Title       string          `yaml:"title"`
 	Description string          `yaml:"description"`
+	Slug        string          `yaml:"slug"`
+	Aliases     []string        `yaml:"aliases"`
 	Published   FrontMatterDate `yaml:"published"`
 	Updated     FrontMatterDate `yaml:"updated"`
 	Menu        string          `yaml:"menu"`
+	Weight      *int            `yaml:"weight"`
+	Series      string          `yaml:"series"`
+	SeriesPart  *int            `yaml:"series-part"`
 	License     string          `yaml:"license"`
 	Author      struct {
 		Name  string `yaml:"name"`
 		Email string `yaml:"email"`
 	} `yaml:"author"`
+	Authors   []string          `yaml:"authors"`
 	OpenGraph *opengraph.Config `yaml:"opengraph"`
 	Search    *bool             `yaml:"search"`
 	Toc       *bool             `yaml:"toc"`
+	Sanitize  bool              `yaml:"sanitize"`
 	Extra     map[string]any    `yaml:"extra"`
+
+	Template    string         `yaml:"template"`
+	TemplateCtx map[string]any `yaml:"template-context"`
+	Layout      struct {
+		WithSidebar *bool `yaml:"with-sidebar"`
+	} `yaml:"layout"`
+	Assets struct {
+		CSS []string `yaml:"css"`
+		JS  []string `yaml:"js"`
+	} `yaml:"assets"`
 }
 
 func Parse(src []byte) (*FrontMatter, []byte, error) {
@@ -1,6 +1,7 @@
 package frontmatter
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -487,6 +488,109 @@ func TestParseWithTocField(t *testing.T) {
 	}
 }
 
+func TestParseWithLayoutFields(t *testing.T) {
+	src := []byte(`---
+title: Landing
+template: landing.html
+template-context:
+  hero: true
+layout:
+  with-sidebar: false
+assets:
+  css:
+    - landing.css
+  js:
+    - /js/landing.js
+    - https://example.com/widget.js
+---
+content
+`)
+
+	metadata, _, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if metadata.Template != "landing.html" {
+		t.Errorf("template=%q, want %q", metadata.Template, "landing.html")
+	}
+	if metadata.TemplateCtx["hero"] != true {
+		t.Errorf("template-context.hero=%v, want true", metadata.TemplateCtx["hero"])
+	}
+	if metadata.Layout.WithSidebar == nil || *metadata.Layout.WithSidebar {
+		t.Errorf("layout.with-sidebar=%v, want false", metadata.Layout.WithSidebar)
+	}
+	if len(metadata.Assets.CSS) != 1 || metadata.Assets.CSS[0] != "landing.css" {
+		t.Errorf("assets.css=%v, want [landing.css]", metadata.Assets.CSS)
+	}
+	if len(metadata.Assets.JS) != 2 || metadata.Assets.JS[1] != "https://example.com/widget.js" {
+		t.Errorf("assets.js=%v", metadata.Assets.JS)
+	}
+
+	metadata, _, err = Parse([]byte("---\ntitle: Test\n---\ncontent\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if metadata.Template != "" || metadata.Layout.WithSidebar != nil {
+		t.Errorf("unexpected layout fields: %q %v", metadata.Template, metadata.Layout.WithSidebar)
+	}
+}
+
+func TestParseWithSlugAndAliases(t *testing.T) {
+	src := []byte(`---
+title: Renamed
+slug: new-name
+aliases:
+  - /old-name/
+  - /2019/01/old.html
+---
+content
+`)
+
+	metadata, _, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if metadata.Slug != "new-name" {
+		t.Errorf("slug=%q, want %q", metadata.Slug, "new-name")
+	}
+	if len(metadata.Aliases) != 2 || metadata.Aliases[0] != "/old-name/" || metadata.Aliases[1] != "/2019/01/old.html" {
+		t.Errorf("aliases=%v", metadata.Aliases)
+	}
+}
+
+func TestParseWithSeriesFields(t *testing.T) {
+	metadata, _, err := Parse([]byte("---\ntitle: Test\nseries: Build log\nseries-part: 3\n---\ncontent\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if metadata.Series != "Build log" {
+		t.Errorf("series=%q, want %q", metadata.Series, "Build log")
+	}
+	if metadata.SeriesPart == nil || *metadata.SeriesPart != 3 {
+		t.Errorf("series-part=%v, want 3", metadata.SeriesPart)
+	}
+
+	metadata, _, err = Parse([]byte("---\ntitle: Test\n---\ncontent\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if metadata.Series != "" || metadata.SeriesPart != nil {
+		t.Errorf("unexpected series fields: %q %v", metadata.Series, metadata.SeriesPart)
+	}
+}
+
+func TestParseWithAuthors(t *testing.T) {
+	metadata, _, err := Parse([]byte("---\ntitle: Test\nauthors:\n  - alice\n  - bob\n---\ncontent\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !slices.Equal(metadata.Authors, []string{"alice", "bob"}) {
+		t.Errorf("authors=%v", metadata.Authors)
+	}
+}
+
 func TestParseEdgeCases(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -3,14 +3,16 @@ package generators
 import (
 	"bytes"
 	"errors"
+	"html/template"
 	"io"
+	"maps"
 	"path"
 	"path/filepath"
 	"slices"
-	"text/template"
 	"time"
 
 	"rafaelmartins.com/p/website/internal/content"
+	"rafaelmartins.com/p/website/internal/frontmatter"
 	"rafaelmartins.com/p/website/internal/opengraph"
 	"rafaelmartins.com/p/website/internal/runner"
 	"rafaelmartins.com/p/website/internal/templates"
@@ -31,10 +33,15 @@ type Content struct {
 	Search            *bool
 	Sources           []*ContentSource
 	IsPost            bool
+	MetadataOnly      bool
 	ExtraDependencies []string
 	Template          string
 	TemplateCtx       map[string]any
 	Pagination        *templates.ContentPagination
+	Section           *templates.ContentSection
+	Archive           *templates.ContentArchive
+	Series            *templates.ContentSeries
+	Author            *templates.ContentAuthor
 	LayoutCtx         *templates.LayoutContext
 
 	OpenGraph                    *opengraph.Config
@@ -45,6 +52,72 @@ type Content struct {
 	og  *opengraph.OpenGraph
 }
 
+// metadata returns the frontmatter of single page sources, that may select
+// the template and layout.
+func (h *Content) metadata() (*frontmatter.FrontMatter, error) {
+	if h.Pagination != nil {
+		return nil, nil
+	}
+
+	for _, src := range h.Sources {
+		if src.File != "" {
+			return content.GetMetadata(src.File)
+		}
+	}
+	return nil, nil
+}
+
+func (h *Content) template(meta *frontmatter.FrontMatter) string {
+	if meta != nil && meta.Template != "" {
+		return meta.Template
+	}
+	return h.Template
+}
+
+func (h *Content) layoutCtx(meta *frontmatter.FrontMatter) *templates.LayoutContext {
+	if meta == nil {
+		return h.LayoutCtx
+	}
+
+	rv := &templates.LayoutContext{}
+	if h.LayoutCtx != nil {
+		rv.WithSidebar = h.LayoutCtx.WithSidebar
+		rv.Stylesheets = slices.Clone(h.LayoutCtx.Stylesheets)
+		rv.Scripts = slices.Clone(h.LayoutCtx.Scripts)
+	}
+	if meta.Layout.WithSidebar != nil {
+		rv.WithSidebar = *meta.Layout.WithSidebar
+	}
+	for _, css := range meta.Assets.CSS {
+		rv.Stylesheets = append(rv.Stylesheets, templates.GetAssetURL(css))
+	}
+	for _, js := range meta.Assets.JS {
+		rv.Scripts = append(rv.Scripts, templates.GetAssetURL(js))
+	}
+	return rv
+}
+
+// archiveEntries groups the entries by the archive month of their
+// publication date.
+func archiveEntries(archive *templates.ContentArchive, entries []*templates.ContentEntry) {
+	for _, entry := range entries {
+		if entry.Post == nil {
+			continue
+		}
+
+		for _, y := range archive.Years {
+			if y.Year != entry.Post.Published.Year() {
+				continue
+			}
+			for _, m := range y.Months {
+				if m.Month == entry.Post.Published.Month() {
+					m.Entries = append(m.Entries, entry)
+				}
+			}
+		}
+	}
+}
+
 func (*Content) GetID() string {
 	return "CONTENT"
 }
@@ -63,6 +136,9 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 		Search:      true,
 		Atom:        &templates.AtomContentEntry{},
 		Pagination:  h.Pagination,
+		Section:     h.Section,
+		Archive:     h.Archive,
+		Author:      h.Author,
 		Extra:       h.TemplateCtx,
 	}
 	if h.Search != nil {
@@ -74,7 +150,10 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 	entries := []*templates.ContentEntry{}
 	mt := ""
 	md := ""
-	var mog *opengraph.Config
+	var (
+		mog  *opengraph.Config
+		meta *frontmatter.FrontMatter
+	)
 
 	for _, src := range h.Sources {
 		if src.File == "" {
@@ -86,7 +165,17 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 			withToc = &h.Toc
 		}
 
-		metadata, toc, body, err := content.Render(src.File, h.URL, withToc)
+		// listings without entry bodies just need the frontmatter
+		var (
+			metadata  *frontmatter.FrontMatter
+			toc, body string
+			err       error
+		)
+		if h.MetadataOnly {
+			metadata, err = content.GetMetadata(src.File)
+		} else {
+			metadata, toc, body, err = content.Render(src.File, h.URL, withToc)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -95,16 +184,24 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 			File:  src.File,
 			URL:   src.URL,
 			Title: metadata.Title,
-			Body:  body,
+			Body:  template.HTML(body),
 		}
 
 		if h.IsPost {
 			entry.Post = &templates.PostContentEntry{
 				Published: metadata.Published.Time,
 				Updated:   metadata.Updated.Time,
+				Series:    h.Series,
 			}
 			entry.Post.Author.Name = metadata.Author.Name
 			entry.Post.Author.Email = metadata.Author.Email
+			for _, id := range metadata.Authors {
+				author, err := templates.GetAuthor(id)
+				if err != nil {
+					return nil, err
+				}
+				entry.Post.Authors = append(entry.Post.Authors, author)
+			}
 			if atomUpdated.Before(entry.Post.Published) {
 				atomUpdated = entry.Post.Published
 			}
@@ -120,13 +217,22 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 				ctx.Title = entry.Title
 			}
 			ctx.Entry = entry
-			ctx.Toc = toc
+			ctx.Toc = template.HTML(toc)
 			if metadata.License != "" {
 				ctx.License = metadata.License
 			}
 			if metadata.Search != nil {
 				ctx.Search = *metadata.Search
 			}
+			if len(metadata.TemplateCtx) > 0 {
+				ctx.Extra = maps.Clone(h.TemplateCtx)
+				if ctx.Extra == nil {
+					ctx.Extra = map[string]any{}
+				}
+				maps.Copy(ctx.Extra, metadata.TemplateCtx)
+			}
+
+			meta = metadata
 
 			mt = metadata.Title
 			md = metadata.Description
@@ -143,6 +249,9 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 		if ctx.Atom.Updated.IsZero() {
 			ctx.Atom.Updated = time.Unix(0, 0)
 		}
+		if h.Archive != nil {
+			archiveEntries(h.Archive, entries)
+		}
 	}
 
 	baseurl := h.URL
@@ -164,14 +273,19 @@ func (h *Content) GetReader() (io.ReadCloser, error) {
 	}
 
 	buf := &bytes.Buffer{}
-	if err := templates.Execute(buf, h.Template, funcMap, h.LayoutCtx, ctx); err != nil {
+	if err := templates.Execute(buf, h.template(meta), funcMap, h.layoutCtx(meta), ctx); err != nil {
 		return nil, err
 	}
 	return io.NopCloser(buf), nil
 }
 
 func (h *Content) GetPaths() ([]string, error) {
-	rv, err := templates.GetPaths(h.Template)
+	meta, err := h.metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	rv, err := templates.GetPaths(h.template(meta))
 	if err != nil {
 		return nil, err
 	}
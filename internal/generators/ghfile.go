@@ -13,6 +13,8 @@ type GithubFile struct {
 	Ref       string
 	Path      string
 	Immutable bool
+
+	ExtraDependencies []string
 }
 
 func (*GithubFile) GetID() string {
@@ -23,8 +25,8 @@ func (g *GithubFile) GetReader() (io.ReadCloser, error) {
 	return github.GetRepositoryFile(g.Owner, g.Repo, g.Path, g.Ref)
 }
 
-func (*GithubFile) GetPaths() ([]string, error) {
-	return nil, nil
+func (g *GithubFile) GetPaths() ([]string, error) {
+	return g.ExtraDependencies, nil
 }
 
 func (g *GithubFile) GetImmutable() bool {
@@ -0,0 +1,120 @@
+package generators
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type GoImport struct {
+	Title    string
+	URL      string
+	Template string
+	Entry    *templates.GoImportContentEntry
+}
+
+func (*GoImport) GetID() string {
+	return "GO-IMPORT"
+}
+
+func (g *GoImport) GetReader() (io.ReadCloser, error) {
+	if g.Entry == nil {
+		return nil, errors.New("go-import: missing entry")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, g.Template, nil, nil, &templates.ContentContext{
+		Title: g.Title,
+		URL:   g.URL,
+		Entry: &templates.ContentEntry{
+			URL:      g.URL,
+			Title:    g.Title,
+			GoImport: g.Entry,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (g *GoImport) GetPaths() ([]string, error) {
+	return templates.GetPaths(g.Template)
+}
+
+func (*GoImport) GetImmutable() bool {
+	return false
+}
+
+func (*GoImport) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
+
+type GoVanityIndex struct {
+	Title       string
+	Description string
+	URL         string
+	Template    string
+	Entries     []*templates.ContentEntry
+	LayoutCtx   *templates.LayoutContext
+
+	OpenGraph         *opengraph.Config
+	OpenGraphImageGen *opengraph.OpenGraphImageGen
+
+	og *opengraph.OpenGraph
+}
+
+func (*GoVanityIndex) GetID() string {
+	return "GO-VANITY"
+}
+
+func (g *GoVanityIndex) GetReader() (io.ReadCloser, error) {
+	og, err := opengraph.New(g.OpenGraphImageGen, false, g.URL, g.Title, g.Description, g.OpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	g.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, g.Template, nil, g.LayoutCtx, &templates.ContentContext{
+		Title:       g.Title,
+		Description: g.Description,
+		URL:         g.URL,
+		Search:      true,
+		Entries:     g.Entries,
+		OpenGraph:   og.GetTemplateContext(),
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (g *GoVanityIndex) GetPaths() ([]string, error) {
+	rv, err := templates.GetPaths(g.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.OpenGraphImageGen != nil {
+		rv = append(rv, g.OpenGraphImageGen.GetPaths()...)
+	}
+	return rv, nil
+}
+
+func (*GoVanityIndex) GetImmutable() bool {
+	return false
+}
+
+func (g *GoVanityIndex) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if g.og != nil {
+			g.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type Json struct {
-	Data any
+	Data              any
+	ExtraDependencies []string
 }
 
 func (Json) GetID() string {
@@ -24,8 +25,8 @@ func (j *Json) GetReader() (io.ReadCloser, error) {
 	return io.NopCloser(buf), nil
 }
 
-func (Json) GetPaths() ([]string, error) {
-	return nil, nil
+func (j Json) GetPaths() ([]string, error) {
+	return j.ExtraDependencies, nil
 }
 
 func (Json) GetImmutable() bool {
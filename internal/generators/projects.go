@@ -0,0 +1,85 @@
+package generators
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type ProjectsOverview struct {
+	Title       string
+	Description string
+	URL         string
+	Template    string
+	Entry       *templates.ProjectsOverviewContentEntry
+	LayoutCtx   *templates.LayoutContext
+
+	ExtraDependencies []string
+
+	OpenGraph         *opengraph.Config
+	OpenGraphImageGen *opengraph.OpenGraphImageGen
+
+	og *opengraph.OpenGraph
+}
+
+func (*ProjectsOverview) GetID() string {
+	return "PROJECTS"
+}
+
+func (p *ProjectsOverview) GetReader() (io.ReadCloser, error) {
+	if p.Entry == nil {
+		return nil, errors.New("projects-overview: missing entry")
+	}
+
+	og, err := opengraph.New(p.OpenGraphImageGen, false, p.URL, p.Title, p.Description, p.OpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, p.Template, nil, p.LayoutCtx, &templates.ContentContext{
+		Title:       p.Title,
+		Description: p.Description,
+		URL:         p.URL,
+		Search:      true,
+		OpenGraph:   og.GetTemplateContext(),
+		Entry: &templates.ContentEntry{
+			URL:      p.URL,
+			Title:    p.Title,
+			Projects: p.Entry,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (p *ProjectsOverview) GetPaths() ([]string, error) {
+	rv, err := templates.GetPaths(p.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.OpenGraphImageGen != nil {
+		rv = append(rv, p.OpenGraphImageGen.GetPaths()...)
+	}
+	return append(rv, p.ExtraDependencies...), nil
+}
+
+func (*ProjectsOverview) GetImmutable() bool {
+	return false
+}
+
+func (p *ProjectsOverview) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if p.og != nil {
+			p.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
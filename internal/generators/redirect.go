@@ -0,0 +1,169 @@
+package generators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type Redirect struct {
+	URL               string
+	Template          string
+	ExtraDependencies []string
+}
+
+func (*Redirect) GetID() string {
+	return "REDIRECT"
+}
+
+func (r *Redirect) GetReader() (io.ReadCloser, error) {
+	if r.URL == "" {
+		return nil, errors.New("redirect: missing url")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, r.Template, nil, nil, &templates.ContentContext{
+		Title: r.URL,
+		URL:   r.URL,
+		Entry: &templates.ContentEntry{
+			URL: r.URL,
+			Redirect: &templates.RedirectContentEntry{
+				URL:      r.URL,
+				External: strings.Contains(r.URL, "://"),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (r *Redirect) GetPaths() ([]string, error) {
+	rv, err := templates.GetPaths(r.Template)
+	if err != nil {
+		return nil, err
+	}
+	return append(rv, r.ExtraDependencies...), nil
+}
+
+func (*Redirect) GetImmutable() bool {
+	return false
+}
+
+func (*Redirect) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
+
+const (
+	RedirectsNetlify = "netlify"
+	RedirectsNginx   = "nginx"
+	RedirectsApache  = "apache"
+)
+
+type RedirectRule struct {
+	From string
+	To   string
+}
+
+// Redirects generates the redirect rules in the format supported by a web
+// server or hosting provider.
+type Redirects struct {
+	Format            string
+	Rules             []*RedirectRule
+	ExtraDependencies []string
+}
+
+func (*Redirects) GetID() string {
+	return "REDIRECTS"
+}
+
+// fromVariants returns the source paths that must be matched. paths without
+// extension match with and without trailing slash.
+func fromVariants(from string) []string {
+	if from == "/" || path.Ext(from) != "" {
+		return []string{from}
+	}
+	from = strings.TrimSuffix(from, "/")
+	return []string{from, from + "/"}
+}
+
+// quote quotes a string for web server configuration files, that only
+// support escaping quotes and backslashes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// apacheQuote quotes a string for apache configuration files, that only
+// unescape quotes, keeping regular expression escapes untouched.
+func apacheQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func (r *Redirects) GetReader() (io.ReadCloser, error) {
+	buf := &bytes.Buffer{}
+
+	switch r.Format {
+	case RedirectsNetlify:
+		for _, rule := range r.Rules {
+			// fields are separated by whitespace, without any escaping
+			if strings.ContainsFunc(rule.From+rule.To, unicode.IsSpace) {
+				return nil, fmt.Errorf("redirects: netlify: unsupported whitespace in rule: %q -> %q", rule.From, rule.To)
+			}
+			for _, from := range fromVariants(rule.From) {
+				fmt.Fprintf(buf, "%s %s 301\n", from, rule.To)
+			}
+		}
+
+	case RedirectsNginx:
+		buf.WriteString("# include in the http block, and add to the server block:\n")
+		buf.WriteString("#   if ($redirect_uri) { return 301 $redirect_uri; }\n")
+		buf.WriteString("map $uri $redirect_uri {\n")
+		for _, rule := range r.Rules {
+			// variables are expanded in map values, and can't be escaped
+			if strings.Contains(rule.To, "$") {
+				return nil, fmt.Errorf("redirects: nginx: unsupported variable in destination: %q", rule.To)
+			}
+			for _, from := range fromVariants(rule.From) {
+				fmt.Fprintf(buf, "    %s %s;\n", quote(from), quote(rule.To))
+			}
+		}
+		buf.WriteString("}\n")
+
+	case RedirectsApache:
+		for _, rule := range r.Rules {
+			from := regexp.QuoteMeta(rule.From)
+			if vars := fromVariants(rule.From); len(vars) > 1 {
+				from = regexp.QuoteMeta(vars[0]) + "/?"
+			}
+			fmt.Fprintf(buf, "RedirectMatch 301 %s %s\n", apacheQuote("^"+from+"$"), apacheQuote(rule.To))
+		}
+
+	default:
+		return nil, fmt.Errorf("redirects: invalid format: %s", r.Format)
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (r *Redirects) GetPaths() ([]string, error) {
+	return r.ExtraDependencies, nil
+}
+
+func (*Redirects) GetImmutable() bool {
+	return false
+}
+
+func (*Redirects) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
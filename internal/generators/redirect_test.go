@@ -0,0 +1,67 @@
+package generators
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testRedirectRules = []*RedirectRule{
+	{From: "/", To: "/blog/"},
+	{From: "/old/", To: "/new/"},
+	{From: "/feed.xml", To: "/atom.xml"},
+	{From: "/quote\"d/", To: "https://example.org/?a=1&b=\"2\""},
+}
+
+func TestRedirectsGolden(t *testing.T) {
+	for _, format := range []string{RedirectsNetlify, RedirectsNginx, RedirectsApache} {
+		t.Run(format, func(t *testing.T) {
+			expected, err := os.ReadFile(filepath.Join("testdata", "redirects."+format))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			r := &Redirects{
+				Format: format,
+				Rules:  testRedirectRules,
+			}
+			rd, err := r.GetReader()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer rd.Close()
+
+			got, err := io.ReadAll(rd)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(got) != string(expected) {
+				t.Errorf("bad output:\n%s\nexpected:\n%s", got, expected)
+			}
+		})
+	}
+}
+
+func TestRedirectsInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format string
+		rule   *RedirectRule
+	}{
+		{"netlify-space-from", RedirectsNetlify, &RedirectRule{From: "/foo bar/", To: "/bar/"}},
+		{"netlify-space-to", RedirectsNetlify, &RedirectRule{From: "/foo/", To: "/foo bar/"}},
+		{"nginx-variable", RedirectsNginx, &RedirectRule{From: "/foo/", To: "/bar/$uri"}},
+		{"invalid-format", "bola", &RedirectRule{From: "/foo/", To: "/bar/"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Redirects{
+				Format: tt.format,
+				Rules:  []*RedirectRule{tt.rule},
+			}
+			if _, err := r.GetReader(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var getRepositoryIssues = `
+query GetRepositoryIssues($owner: String!, $repo: String!, $labels: [String!], $withmilestones: Boolean!, $withissues: Boolean!, $milestonesafter: String, $issuesafter: String) {
+	repository(owner: $owner, name: $repo) {
+		milestones(first: 100, after: $milestonesafter, states: OPEN, orderBy: {field: DUE_DATE, direction: ASC}) @include(if: $withmilestones) {
+			nodes {
+				number
+				title
+				description
+				url
+				dueOn
+				open: issues(states: OPEN) {
+					totalCount
+				}
+				closed: issues(states: CLOSED) {
+					totalCount
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+		}
+		issues(first: 100, after: $issuesafter, states: OPEN, labels: $labels, orderBy: {field: CREATED_AT, direction: DESC}) @include(if: $withissues) {
+			nodes {
+				number
+				title
+				url
+				createdAt
+				author {
+					login
+				}
+				labels(first: 20) {
+					nodes {
+						name
+						color
+					}
+				}
+				milestone {
+					title
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+		}
+	}
+}
+`
+
+type RepositoryMilestone struct {
+	Number       int
+	Title        string
+	Description  string
+	Url          string
+	DueOn        *time.Time
+	OpenIssues   int
+	ClosedIssues int
+}
+
+type RepositoryLabel struct {
+	Name  string
+	Color string
+}
+
+type RepositoryIssue struct {
+	Number    int
+	Title     string
+	Url       string
+	Created   time.Time
+	Author    string
+	Labels    []RepositoryLabel
+	Milestone string
+}
+
+type RepositoryIssues struct {
+	Milestones []*RepositoryMilestone
+	Issues     []*RepositoryIssue
+}
+
+// ByLabel returns the issues tagged with the given label.
+func (r *RepositoryIssues) ByLabel(label string) []*RepositoryIssue {
+	rv := []*RepositoryIssue{}
+	for _, issue := range r.Issues {
+		if slices.ContainsFunc(issue.Labels, func(l RepositoryLabel) bool {
+			return strings.EqualFold(l.Name, label)
+		}) {
+			rv = append(rv, issue)
+		}
+	}
+	return rv
+}
+
+type repositoryIssuesCacheEntry struct {
+	issues *RepositoryIssues
+	err    error
+	once   sync.Once
+}
+
+var (
+	repositoryIssuesCache   = map[string]*repositoryIssuesCacheEntry{}
+	repositoryIssuesCacheMu sync.Mutex
+)
+
+type repositoryPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+func fetchRepositoryIssues(owner string, repo string, labels []string, withMilestones bool) (*RepositoryIssues, error) {
+	rv := &RepositoryIssues{}
+
+	// milestones and issues are paginated independently, and only the
+	// connections with pending pages are requested again
+	variables := map[string]any{
+		"owner":          owner,
+		"repo":           repo,
+		"labels":         labels,
+		"withmilestones": withMilestones,
+		"withissues":     len(labels) > 0,
+	}
+	for variables["withmilestones"] == true || variables["withissues"] == true {
+		o := struct {
+			Repository struct {
+				Milestones *struct {
+					Nodes []struct {
+						Number      int        `json:"number"`
+						Title       string     `json:"title"`
+						Description string     `json:"description"`
+						Url         string     `json:"url"`
+						DueOn       *time.Time `json:"dueOn"`
+						Open        struct {
+							TotalCount int `json:"totalCount"`
+						} `json:"open"`
+						Closed struct {
+							TotalCount int `json:"totalCount"`
+						} `json:"closed"`
+					} `json:"nodes"`
+					PageInfo repositoryPageInfo `json:"pageInfo"`
+				} `json:"milestones"`
+				Issues *struct {
+					Nodes []struct {
+						Number    int       `json:"number"`
+						Title     string    `json:"title"`
+						Url       string    `json:"url"`
+						CreatedAt time.Time `json:"createdAt"`
+						Author    *struct {
+							Login string `json:"login"`
+						} `json:"author"`
+						Labels struct {
+							Nodes []struct {
+								Name  string `json:"name"`
+								Color string `json:"color"`
+							} `json:"nodes"`
+						} `json:"labels"`
+						Milestone *struct {
+							Title string `json:"title"`
+						} `json:"milestone"`
+					} `json:"nodes"`
+					PageInfo repositoryPageInfo `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"repository"`
+		}{}
+
+		if err := GraphqlRequest(getRepositoryIssues, variables, &o); err != nil {
+			return nil, err
+		}
+
+		variables["withmilestones"] = false
+		if m := o.Repository.Milestones; m != nil {
+			for _, milestone := range m.Nodes {
+				ms := &RepositoryMilestone{
+					Number:       milestone.Number,
+					Title:        milestone.Title,
+					Description:  milestone.Description,
+					Url:          milestone.Url,
+					OpenIssues:   milestone.Open.TotalCount,
+					ClosedIssues: milestone.Closed.TotalCount,
+				}
+				if milestone.DueOn != nil {
+					d := milestone.DueOn.UTC()
+					ms.DueOn = &d
+				}
+				rv.Milestones = append(rv.Milestones, ms)
+			}
+
+			if m.PageInfo.HasNextPage {
+				variables["withmilestones"] = true
+				variables["milestonesafter"] = m.PageInfo.EndCursor
+			}
+		}
+
+		variables["withissues"] = false
+		if i := o.Repository.Issues; i != nil {
+			for _, issue := range i.Nodes {
+				is := &RepositoryIssue{
+					Number:  issue.Number,
+					Title:   issue.Title,
+					Url:     issue.Url,
+					Created: issue.CreatedAt.UTC(),
+				}
+				if issue.Author != nil {
+					is.Author = issue.Author.Login
+				}
+				if issue.Milestone != nil {
+					is.Milestone = issue.Milestone.Title
+				}
+				for _, label := range issue.Labels.Nodes {
+					is.Labels = append(is.Labels, RepositoryLabel(label))
+				}
+				rv.Issues = append(rv.Issues, is)
+			}
+
+			if i.PageInfo.HasNextPage {
+				variables["withissues"] = true
+				variables["issuesafter"] = i.PageInfo.EndCursor
+			}
+		}
+	}
+	return rv, nil
+}
+
+// GetRepositoryIssues fetches the open milestones and the open issues tagged
+// with any of the given labels once, and shares them between all the projects
+// built from the same repository.
+func GetRepositoryIssues(owner string, repo string, labels []string, withMilestones bool) (*RepositoryIssues, error) {
+	key := fmt.Sprintf("%s/%s/%t/%s", owner, repo, withMilestones, strings.Join(labels, "\x00"))
+
+	repositoryIssuesCacheMu.Lock()
+	entry, found := repositoryIssuesCache[key]
+	if !found {
+		entry = &repositoryIssuesCacheEntry{}
+		repositoryIssuesCache[key] = entry
+	}
+	repositoryIssuesCacheMu.Unlock()
+
+	entry.once.Do(func() {
+		entry.issues, entry.err = fetchRepositoryIssues(owner, repo, labels, withMilestones)
+	})
+	return entry.issues, entry.err
+}
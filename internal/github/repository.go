@@ -1,6 +1,9 @@
 package github
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -8,10 +11,14 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 var getRepository = `
-query GetRepository($owner: String!, $repo: String!, $rollingtag: String!, $readmeref: String, $docsref: String, $headersref: String) {
+query GetRepository($owner: String!, $repo: String!, $rollingtag: String!) {
 	repository(owner: $owner, name: $repo) {
 		description
 		homepageUrl
@@ -39,10 +46,13 @@ query GetRepository($owner: String!, $repo: String!, $rollingtag: String!, $read
 			tagName
 			url
 			description
+			publishedAt
 			releaseAssets(first: 100) {
 				nodes {
 					name
 					downloadUrl
+					size
+					downloadCount
 				}
 				totalCount
 			}
@@ -53,75 +63,86 @@ query GetRepository($owner: String!, $repo: String!, $rollingtag: String!, $read
 				nodes {
 					name
 					downloadUrl
+					size
+					downloadCount
 				}
 				totalCount
 			}
 		}
-		releases(first: 100, orderBy:{field: NAME, direction: DESC}) {
+		releases(first: 100, orderBy:{field: CREATED_AT, direction: DESC}) {
 			nodes {
+				name
 				tagName
+				url
+				description
+				publishedAt
 				isDraft
 				isPrerelease
-			}
-			totalCount
-		}
-		readme: object(expression: $readmeref) {
-			... on Blob {
-				text
-				isBinary
-				isTruncated
-			}
-		}
-		docs: object(expression: $docsref) {
-			... on Tree {
-				entries {
-					name
-					type
-					object {
-						... on Blob {
-							text
-							isBinary
-							isTruncated
-						}
+				releaseAssets(first: 100) {
+					nodes {
+						name
+						downloadUrl
+						size
+						downloadCount
 					}
+					totalCount
 				}
 			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
 		}
-		headers: object(expression: $headersref) {
-			... on Tree {
-				entries {
-					name
-					type
-					object {
-						... on Blob {
-							text
-							isBinary
-							isTruncated
-						}
-						... on Tree {
-							entries {
-								name
-								type
-								object {
-									... on Blob {
-										text
-										isBinary
-										isTruncated
-									}
-								}
-							}
-						}
+	}
+}
+`
+
+var getRepositoryReleases = `
+query GetRepositoryReleases($owner: String!, $repo: String!, $releasesafter: String) {
+	repository(owner: $owner, name: $repo) {
+		releases(first: 100, after: $releasesafter, orderBy:{field: CREATED_AT, direction: DESC}) {
+			nodes {
+				name
+				tagName
+				url
+				description
+				publishedAt
+				isDraft
+				isPrerelease
+				releaseAssets(first: 100) {
+					nodes {
+						name
+						downloadUrl
+						size
+						downloadCount
 					}
+					totalCount
 				}
 			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
 		}
 	}
 }
 `
 
 type RepositoryReleaseAsset struct {
+	Name          string
+	DownloadUrl   string
+	Size          int64
+	DownloadCount int
+}
+
+type RepositoryRelease struct {
 	Name        string
-	DownloadUrl string
+	Tag         string
+	Url         string
+	Description string
+	Published   time.Time
+	Prerelease  bool
+	Assets      []RepositoryReleaseAsset
 }
 
 type RepositoryLatestRelease struct {
@@ -129,6 +150,7 @@ type RepositoryLatestRelease struct {
 	Tag         string
 	Url         string
 	Description string
+	Published   time.Time
 	Assets      []RepositoryReleaseAsset
 }
 
@@ -141,6 +163,7 @@ type RepositoryFile struct {
 
 	owner string
 	repo  string
+	path  string
 	ref   string
 
 	data []byte
@@ -159,36 +182,147 @@ type Repository struct {
 	LicenseSpdx    string
 	LatestRelease  *RepositoryLatestRelease
 	RollingRelease *RepositoryRollingRelease
-	Releases       []string
+	Releases       []*RepositoryRelease
 	Readme         *RepositoryFile
 	Docs           []*RepositoryFile
 	Headers        []*RepositoryFile
 
 	owner      string
 	repo       string
+	subdir     string
 	headersDir *string
 	localDir   *string
+	files      []string
 }
 
-type repositoryBlob struct {
-	Text        *string `json:"text"`
-	IsBinary    bool    `json:"isBinary"`
-	IsTruncated bool    `json:"isTruncated"`
+type repositoryReleaseAsset struct {
+	Name          string `json:"name"`
+	DownloadUrl   string `json:"downloadUrl"`
+	Size          int64  `json:"size"`
+	DownloadCount int    `json:"downloadCount"`
 }
 
-func newRepositoryFile(owner string, repo string, path string, ref string, blob *repositoryBlob, localDir *string) *RepositoryFile {
-	rv := &RepositoryFile{
-		Name:     path,
-		owner:    owner,
-		repo:     repo,
-		ref:      ref,
-		localDir: localDir,
+type repositoryReleases struct {
+	Nodes []struct {
+		Name          string    `json:"name"`
+		TagName       string    `json:"tagName"`
+		Url           string    `json:"url"`
+		Description   string    `json:"description"`
+		PublishedAt   time.Time `json:"publishedAt"`
+		IsDraft       bool      `json:"isDraft"`
+		IsPrerelease  bool      `json:"isPrerelease"`
+		ReleaseAssets struct {
+			Nodes      []repositoryReleaseAsset `json:"nodes"`
+			TotalCount int                      `json:"totalCount"`
+		} `json:"releaseAssets"`
+	} `json:"nodes"`
+	PageInfo repositoryPageInfo `json:"pageInfo"`
+}
+
+// fetchRepositoryTree lists the files of the repository tree recursively.
+// only names are fetched, contents are requested when needed.
+type repositoryTree struct {
+	Tree []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
+
+func getRepositoryTree(owner string, repo string, sha string, recursive bool) (*repositoryTree, error) {
+	p := path.Join("repos", owner, repo, "git", "trees", sha)
+	if recursive {
+		p += "?recursive=1"
 	}
 
-	if localDir != nil && blob != nil && blob.Text != nil && !blob.IsBinary && !blob.IsTruncated {
-		rv.data = []byte(*blob.Text)
+	body, err := Request("GET", p, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	rv := &repositoryTree{}
+	if err := json.NewDecoder(body).Decode(rv); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func walkRepositoryTree(owner string, repo string, sha string, prefix string) ([]string, error) {
+	t, err := getRepositoryTree(owner, repo, sha, false)
+	if err != nil {
+		return nil, err
+	}
+	if t.Truncated {
+		return nil, fmt.Errorf("github: repository: %s/%s: tree truncated: %s", owner, repo, prefix)
+	}
+
+	rv := []string{}
+	for _, entry := range t.Tree {
+		switch entry.Type {
+		case "blob":
+			rv = append(rv, path.Join(prefix, entry.Path))
+
+		case "tree":
+			files, err := walkRepositoryTree(owner, repo, entry.SHA, path.Join(prefix, entry.Path))
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, files...)
+		}
+	}
+	return rv, nil
+}
+
+func fetchRepositoryTree(owner string, repo string, sha string) ([]string, error) {
+	t, err := getRepositoryTree(owner, repo, sha, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// the api has a limit of entries for recursive trees, walk the subtrees
+	// instead
+	if t.Truncated {
+		return walkRepositoryTree(owner, repo, sha, "")
+	}
+
+	rv := []string{}
+	for _, entry := range t.Tree {
+		if entry.Type == "blob" {
+			rv = append(rv, entry.Path)
+		}
+	}
+	return rv, nil
+}
+
+type repositoryCacheEntry struct {
+	repo *Repository
+	err  error
+	once sync.Once
+}
+
+var (
+	repositoryCache   = map[string]*repositoryCacheEntry{}
+	repositoryCacheMu sync.Mutex
+)
+
+func cutPathPrefix(p string, prefix string) (string, bool) {
+	if prefix == "" || prefix == "." {
+		return p, true
+	}
+	return strings.CutPrefix(p, prefix+"/")
+}
+
+func newRepositoryFile(r *Repository, name string, ref string) *RepositoryFile {
+	return &RepositoryFile{
+		Name:     name,
+		owner:    r.owner,
+		repo:     r.repo,
+		path:     path.Join(r.subdir, name),
+		ref:      ref,
+		localDir: r.localDir,
 	}
-	return rv
 }
 
 func (r *RepositoryFile) Read() ([]byte, error) {
@@ -201,9 +335,9 @@ func (r *RepositoryFile) Read() ([]byte, error) {
 		err error
 	)
 	if r.localDir != nil {
-		fp, err = os.Open(filepath.Join(*r.localDir, r.Name))
+		fp, err = os.Open(filepath.Join(*r.localDir, filepath.FromSlash(r.path)))
 	} else {
-		fp, err = GetRepositoryFile(r.owner, r.repo, r.Name, r.ref)
+		fp, err = GetRepositoryFile(r.owner, r.repo, r.path, r.ref)
 	}
 	if err != nil {
 		return nil, err
@@ -221,7 +355,7 @@ func (r *RepositoryFile) Read() ([]byte, error) {
 	return rv, nil
 }
 
-func GetRepository(owner string, repo string, rollingtag string, headersDir *string, localDir *string) (*Repository, error) {
+func fetchRepository(owner string, repo string, rollingtag string, withTree bool) (*Repository, error) {
 	o := struct {
 		Repository struct {
 			Description      string `json:"description"`
@@ -244,58 +378,24 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 				SpdxId string `json:"spdxId"`
 			} `json:"licenseInfo"`
 			LatestRelease *struct {
-				Name          string `json:"name"`
-				TagName       string `json:"tagName"`
-				Url           string `json:"url"`
-				Description   string `json:"description"`
+				Name          string    `json:"name"`
+				TagName       string    `json:"tagName"`
+				Url           string    `json:"url"`
+				Description   string    `json:"description"`
+				PublishedAt   time.Time `json:"publishedAt"`
 				ReleaseAssets struct {
-					Nodes []struct {
-						Name        string `json:"name"`
-						DownloadUrl string `json:"downloadUrl"`
-					} `json:"nodes"`
-					TotalCount int `json:"totalCount"`
+					Nodes      []repositoryReleaseAsset `json:"nodes"`
+					TotalCount int                      `json:"totalCount"`
 				} `json:"releaseAssets"`
 			} `json:"latestRelease"`
 			Rolling *struct {
 				TagName       string `json:"tagName"`
 				ReleaseAssets struct {
-					Nodes []struct {
-						Name        string `json:"name"`
-						DownloadUrl string `json:"downloadUrl"`
-					} `json:"nodes"`
-					TotalCount int `json:"totalCount"`
+					Nodes      []repositoryReleaseAsset `json:"nodes"`
+					TotalCount int                      `json:"totalCount"`
 				} `json:"releaseAssets"`
 			} `json:"rolling"`
-			Releases struct {
-				Nodes []struct {
-					TagName      string `json:"tagName"`
-					IsDraft      bool   `json:"isDraft"`
-					IsPrerelease bool   `json:"isPrerelease"`
-				} `json:"nodes"`
-				TotalCount int `json:"totalCount"`
-			} `json:"releases"`
-			Readme *repositoryBlob `json:"readme"`
-			Docs   *struct {
-				Entries []struct {
-					Name   string         `json:"name"`
-					Type   string         `json:"type"`
-					Object repositoryBlob `json:"object"`
-				} `json:"entries"`
-			} `json:"docs"`
-			Headers *struct {
-				Entries []struct {
-					Name   string `json:"name"`
-					Type   string `json:"type"`
-					Object struct {
-						repositoryBlob
-						Entries []struct {
-							Name   string         `json:"name"`
-							Type   string         `json:"type"`
-							Object repositoryBlob `json:"object"`
-						} `json:"entries"`
-					} `json:"object"`
-				} `json:"entries"`
-			} `json:"headers"`
+			Releases repositoryReleases `json:"releases"`
 		} `json:"repository"`
 	}{}
 
@@ -304,18 +404,6 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 		"repo":       repo,
 		"rollingtag": rollingtag,
 	}
-	if localDir == nil {
-		variables["readmeref"] = "HEAD:README.md"
-		variables["docsref"] = "HEAD:docs"
-		if headersDir != nil {
-			if *headersDir == "." {
-				variables["headersref"] = "HEAD:"
-			} else {
-				variables["headersref"] = "HEAD:" + filepath.ToSlash(filepath.Clean(*headersDir))
-			}
-		}
-	}
-
 	if err := GraphqlRequest(getRepository, variables, &o); err != nil {
 		return nil, err
 	}
@@ -339,12 +427,6 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 		)
 	}
 
-	if o.Repository.Releases.TotalCount > 100 {
-		return nil, fmt.Errorf("github: repository: %s/%s: more than 100 releases: %d",
-			owner, repo, o.Repository.Releases.TotalCount,
-		)
-	}
-
 	rv := &Repository{
 		Description:   o.Repository.Description,
 		HomepageUrl:   o.Repository.HomepageUrl,
@@ -354,10 +436,8 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 		Stars:         o.Repository.StargazerCount,
 		Watchers:      o.Repository.Watchers.TotalCount,
 
-		owner:      owner,
-		repo:       repo,
-		headersDir: headersDir,
-		localDir:   localDir,
+		owner: owner,
+		repo:  repo,
 	}
 
 	if o.Repository.LatestRelease != nil {
@@ -366,6 +446,7 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 			Tag:         o.Repository.LatestRelease.TagName,
 			Url:         o.Repository.LatestRelease.Url,
 			Description: o.Repository.LatestRelease.Description,
+			Published:   o.Repository.LatestRelease.PublishedAt.UTC(),
 		}
 		for _, asset := range o.Repository.LatestRelease.ReleaseAssets.Nodes {
 			rv.LatestRelease.Assets = append(rv.LatestRelease.Assets, RepositoryReleaseAsset(asset))
@@ -379,80 +460,159 @@ func GetRepository(owner string, repo string, rollingtag string, headersDir *str
 		}
 	}
 
-	for _, release := range o.Repository.Releases.Nodes {
-		if !release.IsDraft && !release.IsPrerelease {
-			rv.Releases = append(rv.Releases, release.TagName)
+	releases := &o.Repository.Releases
+	for {
+		for _, release := range releases.Nodes {
+			if release.IsDraft {
+				continue
+			}
+
+			if release.ReleaseAssets.TotalCount > 100 {
+				return nil, fmt.Errorf("github: repository: %s/%s: release %s with more than 100 assets: %d",
+					owner, repo, release.TagName, release.ReleaseAssets.TotalCount,
+				)
+			}
+
+			r := &RepositoryRelease{
+				Name:        release.Name,
+				Tag:         release.TagName,
+				Url:         release.Url,
+				Description: release.Description,
+				Published:   release.PublishedAt.UTC(),
+				Prerelease:  release.IsPrerelease,
+			}
+			for _, asset := range release.ReleaseAssets.Nodes {
+				r.Assets = append(r.Assets, RepositoryReleaseAsset(asset))
+			}
+			rv.Releases = append(rv.Releases, r)
+		}
+
+		if !releases.PageInfo.HasNextPage {
+			break
 		}
+
+		p := struct {
+			Repository struct {
+				Releases repositoryReleases `json:"releases"`
+			} `json:"repository"`
+		}{}
+		variables := map[string]any{
+			"owner":         owner,
+			"repo":          repo,
+			"releasesafter": releases.PageInfo.EndCursor,
+		}
+		if err := GraphqlRequest(getRepositoryReleases, variables, &p); err != nil {
+			return nil, err
+		}
+		releases = &p.Repository.Releases
 	}
 
 	if o.Repository.LicenseInfo != nil && o.Repository.LicenseInfo.SpdxId != "NOASSERTION" {
 		rv.LicenseSpdx = o.Repository.LicenseInfo.SpdxId
 	}
 
+	if withTree {
+		files, err := fetchRepositoryTree(owner, repo, rv.Head)
+		if err != nil {
+			return nil, err
+		}
+		rv.files = files
+	}
+	return rv, nil
+}
+
+// GetRepository fetches the repository metadata and file tree once, and shares
+// them between all the projects built from the same repository.
+func GetRepository(owner string, repo string, rollingtag string, subdir string, headersDir *string, localDir *string) (*Repository, error) {
+	key := strings.Join([]string{owner, repo, rollingtag, strconv.FormatBool(localDir == nil)}, "/")
+
+	repositoryCacheMu.Lock()
+	entry, found := repositoryCache[key]
+	if !found {
+		entry = &repositoryCacheEntry{}
+		repositoryCache[key] = entry
+	}
+	repositoryCacheMu.Unlock()
+
+	entry.once.Do(func() {
+		entry.repo, entry.err = fetchRepository(owner, repo, rollingtag, localDir == nil)
+	})
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	rv := *entry.repo
+	rv.subdir = path.Clean(filepath.ToSlash(subdir))
+	if rv.subdir == "." {
+		rv.subdir = ""
+	}
+	rv.headersDir = headersDir
+	rv.localDir = localDir
+
 	if localDir != nil {
 		if err := rv.ReloadLocalDir(); err != nil {
 			return nil, err
 		}
-		return rv, nil
+		return &rv, nil
 	}
 
-	if o.Repository.Readme != nil {
-		rv.Readme = newRepositoryFile(owner, repo, "README.md", o.Repository.Head.Oid, o.Repository.Readme, nil)
+	headers := "."
+	if headersDir != nil {
+		headers = path.Clean(filepath.ToSlash(*headersDir))
 	}
 
-	if o.Repository.Docs != nil {
-		for _, doc := range o.Repository.Docs.Entries {
-			if doc.Type != "blob" || (path.Ext(doc.Name) != ".md" && path.Ext(doc.Name) != ".markdown") {
-				continue
-			}
-			rv.Docs = append(rv.Docs, newRepositoryFile(owner, repo, path.Join("docs", doc.Name), o.Repository.Head.Oid, &doc.Object, nil))
+	for _, f := range rv.files {
+		rel, found := cutPathPrefix(f, rv.subdir)
+		if !found {
+			continue
 		}
-	}
 
-	if o.Repository.Headers != nil {
-		prefix := ""
-		if headersDir != nil {
-			prefix = *headersDir
+		if rel == "README.md" {
+			rv.Readme = newRepositoryFile(&rv, rel, rv.Head)
+			continue
 		}
-		for _, entry := range o.Repository.Headers.Entries {
-			if entry.Type == "tree" {
-				for _, subEntry := range entry.Object.Entries {
-					if subEntry.Type != "blob" || path.Ext(subEntry.Name) != ".h" {
-						continue
-					}
-					rv.Headers = append(rv.Headers, newRepositoryFile(owner, repo, path.Join(prefix, entry.Name, subEntry.Name), o.Repository.Head.Oid, &subEntry.Object, nil))
-				}
-				continue
-			}
 
-			if entry.Type != "blob" || path.Ext(entry.Name) != ".h" {
-				continue
+		if strings.HasPrefix(rel, "docs/") && (path.Ext(rel) == ".md" || path.Ext(rel) == ".markdown") {
+			rv.Docs = append(rv.Docs, newRepositoryFile(&rv, rel, rv.Head))
+		}
+
+		// headers are looked up in the headers directory and its direct subdirectories
+		if headersDir != nil && path.Ext(rel) == ".h" {
+			if h, found := cutPathPrefix(rel, headers); found && strings.Count(h, "/") <= 1 {
+				rv.Headers = append(rv.Headers, newRepositoryFile(&rv, rel, rv.Head))
 			}
-			rv.Headers = append(rv.Headers, newRepositoryFile(owner, repo, path.Join(prefix, entry.Name), o.Repository.Head.Oid, &entry.Object.repositoryBlob, nil))
 		}
 	}
-	return rv, nil
+	return &rv, nil
 }
 
 func (r *Repository) ReloadLocalDir() error {
 	if r.localDir == nil {
 		return nil
 	}
+	root := filepath.Join(*r.localDir, filepath.FromSlash(r.subdir))
 
 	r.Readme = nil
-	if _, err := os.Stat(filepath.Join(*r.localDir, "README.md")); err == nil {
-		r.Readme = newRepositoryFile(r.owner, r.repo, "README.md", "", nil, r.localDir)
+	if _, err := os.Stat(filepath.Join(root, "README.md")); err == nil {
+		r.Readme = newRepositoryFile(r, "README.md", "")
 	}
 
 	r.Docs = nil
-	l, err := os.ReadDir(filepath.Join(*r.localDir, "docs"))
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return err
-	}
-	for _, e := range l {
-		if e.Type().IsRegular() {
-			r.Docs = append(r.Docs, newRepositoryFile(r.owner, r.repo, path.Join("docs", e.Name()), "", nil, r.localDir))
+	if err := filepath.WalkDir(filepath.Join(root, "docs"), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
+
+		if ext := filepath.Ext(p); d.Type().IsRegular() && (ext == ".md" || ext == ".markdown") {
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			r.Docs = append(r.Docs, newRepositoryFile(r, filepath.ToSlash(rel), ""))
+		}
+		return nil
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
 	}
 
 	r.Headers = nil
@@ -460,25 +620,25 @@ func (r *Repository) ReloadLocalDir() error {
 	if r.headersDir != nil {
 		prefix = *r.headersDir
 	}
-	l, err = os.ReadDir(filepath.Join(*r.localDir, prefix))
+	l, err := os.ReadDir(filepath.Join(root, prefix))
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
 	for _, e := range l {
 		if e.IsDir() {
-			l2, err := os.ReadDir(filepath.Join(*r.localDir, prefix, e.Name()))
+			l2, err := os.ReadDir(filepath.Join(root, prefix, e.Name()))
 			if err != nil {
 				return err
 			}
 
 			for _, e2 := range l2 {
 				if e2.Type().IsRegular() {
-					r.Headers = append(r.Headers, newRepositoryFile(r.owner, r.repo, path.Join(prefix, e.Name(), e2.Name()), "", nil, r.localDir))
+					r.Headers = append(r.Headers, newRepositoryFile(r, path.Join(prefix, e.Name(), e2.Name()), ""))
 				}
 			}
 		}
 		if e.Type().IsRegular() {
-			r.Headers = append(r.Headers, newRepositoryFile(r.owner, r.repo, path.Join(prefix, e.Name()), "", nil, r.localDir))
+			r.Headers = append(r.Headers, newRepositoryFile(r, path.Join(prefix, e.Name()), ""))
 		}
 	}
 	return nil
@@ -502,3 +662,98 @@ func GetRepositoryFile(owner string, repo string, ppath string, ref string) (io.
 	}
 	return Request("GET", path.Join("repos", owner, repo, "contents", ppath+qs), headers, nil)
 }
+
+// ListTreeFiles lists the files from the repository tree, without fetching
+// their contents. they are fetched individually when read.
+func (r *Repository) ListTreeFiles(filter func(name string) bool) []*RepositoryFile {
+	rv := []*RepositoryFile{}
+	for _, f := range r.files {
+		name, found := cutPathPrefix(f, r.subdir)
+		if !found || (filter != nil && !filter(name)) {
+			continue
+		}
+		rv = append(rv, newRepositoryFile(r, name, r.Head))
+	}
+	return rv
+}
+
+func (r *Repository) ListFiles(filter func(name string) bool) ([]*RepositoryFile, error) {
+	rv := []*RepositoryFile{}
+
+	if r.localDir != nil {
+		root := filepath.Join(*r.localDir, filepath.FromSlash(r.subdir))
+		if err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if n := d.Name(); p != root && (n[0] == '.' || n[0] == '_') {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			if d.Type().IsRegular() && (filter == nil || filter(rel)) {
+				rv = append(rv, newRepositoryFile(r, rel, ""))
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return rv, nil
+	}
+
+	body, err := Request("GET", path.Join("repos", r.owner, r.repo, "tarball", r.Head), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// github archives have a single top level directory, named after the commit
+		_, name, found := strings.Cut(hdr.Name, "/")
+		if !found {
+			continue
+		}
+		name, found = cutPathPrefix(name, r.subdir)
+		if !found || (filter != nil && !filter(name)) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		f := newRepositoryFile(r, name, r.Head)
+		f.data = data
+		rv = append(rv, f)
+	}
+	return rv, nil
+}
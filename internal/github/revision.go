@@ -0,0 +1,92 @@
+package github
+
+import (
+	"strconv"
+	"strings"
+)
+
+var getRepositoryRevision = `
+query GetRepositoryRevision($owner: String!, $repo: String!) {
+	repository(owner: $owner, name: $repo) {
+		head: object(expression: "HEAD") {
+			... on Commit {
+				oid
+			}
+		}
+		stargazerCount
+		latestRelease {
+			tagName
+		}
+	}
+}
+`
+
+// RepositoryRevision identifies the state of a repository that is relevant
+// for the generated pages, without fetching the whole repository.
+type RepositoryRevision struct {
+	Head          string `json:"head"`
+	LatestRelease string `json:"latest-release,omitempty"`
+	Stars         int    `json:"stars"`
+}
+
+func (r *Repository) GetRevision() *RepositoryRevision {
+	rv := &RepositoryRevision{
+		Head:  r.Head,
+		Stars: r.Stars,
+	}
+	if r.LatestRelease != nil {
+		rv.LatestRelease = r.LatestRelease.Tag
+	}
+	return rv
+}
+
+// GetRepositoryRevision is not cached, it is used to revalidate the cached
+// repositories.
+func GetRepositoryRevision(owner string, repo string) (*RepositoryRevision, error) {
+	o := struct {
+		Repository struct {
+			Head struct {
+				Oid string `json:"oid"`
+			} `json:"head"`
+			StargazerCount int `json:"stargazerCount"`
+			LatestRelease  *struct {
+				TagName string `json:"tagName"`
+			} `json:"latestRelease"`
+		} `json:"repository"`
+	}{}
+
+	if err := GraphqlRequest(getRepositoryRevision, map[string]any{
+		"owner": owner,
+		"repo":  repo,
+	}, &o); err != nil {
+		return nil, err
+	}
+
+	rv := &RepositoryRevision{
+		Head:  o.Repository.Head.Oid,
+		Stars: o.Repository.StargazerCount,
+	}
+	if o.Repository.LatestRelease != nil {
+		rv.LatestRelease = o.Repository.LatestRelease.TagName
+	}
+	return rv, nil
+}
+
+// ExpireRepository drops the cached remote repository if it does not match
+// the given revision, forcing the next GetRepository call to fetch it again.
+func ExpireRepository(owner string, repo string, rollingtag string, rev *RepositoryRevision) {
+	key := strings.Join([]string{owner, repo, rollingtag, strconv.FormatBool(true)}, "/")
+
+	repositoryCacheMu.Lock()
+	defer repositoryCacheMu.Unlock()
+
+	entry, found := repositoryCache[key]
+	if !found {
+		return
+	}
+
+	if entry.repo != nil && rev != nil && *entry.repo.GetRevision() == *rev {
+		return
+	}
+	delete(repositoryCache, key)
+}
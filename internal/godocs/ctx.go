@@ -0,0 +1,292 @@
+package godocs
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"html/template"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/alecthomas/repr"
+)
+
+type TemplateCtx struct {
+	ImportPath string
+	Name       string
+	Synopsis   string
+	Doc        template.HTML
+
+	Files []*FileCtx
+
+	Constants []*DeclCtx
+	Variables []*DeclCtx
+	Functions []*DeclCtx
+	Types     []*TypeCtx
+	Examples  []*ExampleCtx
+
+	Packages []*PackageCtx
+}
+
+func (t *TemplateCtx) Dump(w io.Writer) {
+	repr.New(w).Println(t)
+}
+
+type FileCtx struct {
+	Name string
+	Link string
+}
+
+type DeclCtx struct {
+	ID          string
+	Name        string
+	Decl        template.HTML
+	Description template.HTML
+	Link        string
+	Examples    []*ExampleCtx
+}
+
+type TypeCtx struct {
+	DeclCtx
+
+	Constants []*DeclCtx
+	Variables []*DeclCtx
+	Functions []*DeclCtx
+	Methods   []*DeclCtx
+}
+
+type ExampleCtx struct {
+	ID          string
+	Name        string
+	Description template.HTML
+	Code        template.HTML
+	Output      string
+}
+
+type PackageCtx struct {
+	ImportPath string
+	Name       string
+	Synopsis   string
+	URL        string
+}
+
+type ctxBuilder struct {
+	pkg     *Package
+	baseUrl string
+}
+
+func (b *ctxBuilder) html(text string) template.HTML {
+	if text == "" {
+		return ""
+	}
+
+	// links to other packages point to pkg.go.dev, we only render our own packages
+	p := b.pkg.Doc.Printer()
+	p.DocLinkBaseURL = "https://pkg.go.dev"
+	return template.HTML(p.HTML(b.pkg.Doc.Parser().Parse(text)))
+}
+
+func (b *ctxBuilder) link(node ast.Node) string {
+	start := b.pkg.FileSet.Position(node.Pos())
+	end := b.pkg.FileSet.Position(node.End())
+
+	rv := fmt.Sprintf("%s/%s#L%d", b.baseUrl, start.Filename, start.Line)
+	if end.Line != start.Line {
+		rv += fmt.Sprintf("-L%d", end.Line)
+	}
+	return rv
+}
+
+func (b *ctxBuilder) print(node any) (string, error) {
+	buf := &bytes.Buffer{}
+	cfg := printer.Config{
+		Mode:     printer.UseSpaces | printer.TabIndent,
+		Tabwidth: 8,
+	}
+	if err := cfg.Fprint(buf, b.pkg.FileSet, node); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (b *ctxBuilder) decl(id string, name string, decl ast.Decl, description string) (*DeclCtx, error) {
+	src, err := b.print(decl)
+	if err != nil {
+		return nil, err
+	}
+
+	hl, err := highlight(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeclCtx{
+		ID:          id,
+		Name:        name,
+		Decl:        hl,
+		Description: b.html(description),
+		Link:        b.link(decl),
+	}, nil
+}
+
+func (b *ctxBuilder) values(values []*doc.Value) ([]*DeclCtx, error) {
+	rv := []*DeclCtx{}
+	for _, v := range values {
+		if len(v.Names) == 0 {
+			continue
+		}
+
+		d, err := b.decl(v.Names[0], strings.Join(v.Names, ", "), v.Decl, v.Doc)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, d)
+	}
+	return rv, nil
+}
+
+func (b *ctxBuilder) examples(examples []*doc.Example, prefix string) ([]*ExampleCtx, error) {
+	rv := []*ExampleCtx{}
+	for _, ex := range examples {
+		// the output comment is rendered separately
+		comments := []*ast.CommentGroup{}
+		for _, c := range ex.Comments {
+			t := strings.ToLower(strings.TrimSpace(c.Text()))
+			if !strings.HasPrefix(t, "output:") && !strings.HasPrefix(t, "unordered output:") {
+				comments = append(comments, c)
+			}
+		}
+
+		src, err := b.print(&printer.CommentedNode{
+			Node:     ex.Code,
+			Comments: comments,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// examples are usually block statements, drop the braces and unindent
+		if _, ok := ex.Code.(*ast.BlockStmt); ok {
+			src = strings.TrimSuffix(strings.TrimPrefix(src, "{\n"), "}")
+			lines := []string{}
+			for line := range strings.Lines(src) {
+				lines = append(lines, strings.TrimPrefix(line, "\t"))
+			}
+			src = strings.Join(lines, "")
+		}
+		src = strings.TrimSpace(src)
+
+		hl, err := highlight(src)
+		if err != nil {
+			return nil, err
+		}
+
+		id := "example"
+		if prefix != "" {
+			id += "-" + prefix
+		}
+		name := "Example"
+		if ex.Suffix != "" {
+			id += "-" + ex.Suffix
+			name += " (" + ex.Suffix + ")"
+		}
+
+		rv = append(rv, &ExampleCtx{
+			ID:          id,
+			Name:        name,
+			Description: b.html(ex.Doc),
+			Code:        hl,
+			Output:      ex.Output,
+		})
+	}
+	return rv, nil
+}
+
+func (b *ctxBuilder) funcs(funcs []*doc.Func, prefix string) ([]*DeclCtx, error) {
+	rv := []*DeclCtx{}
+	for _, f := range funcs {
+		id := f.Name
+		if prefix != "" {
+			id = prefix + "." + f.Name
+		}
+
+		d, err := b.decl(id, f.Name, f.Decl, f.Doc)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Examples, err = b.examples(f.Examples, id)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, d)
+	}
+	return rv, nil
+}
+
+func NewTemplateCtx(pkg *Package, baseUrl string, packages []*PackageCtx) (*TemplateCtx, error) {
+	b := &ctxBuilder{
+		pkg:     pkg,
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+	}
+
+	rv := &TemplateCtx{
+		ImportPath: pkg.ImportPath,
+		Name:       pkg.Doc.Name,
+		Synopsis:   pkg.Doc.Synopsis(pkg.Doc.Doc),
+		Packages:   packages,
+	}
+	rv.Doc = b.html(pkg.Doc.Doc)
+
+	for _, f := range pkg.Files {
+		rv.Files = append(rv.Files, &FileCtx{
+			Name: path.Base(f),
+			Link: b.baseUrl + "/" + f,
+		})
+	}
+
+	var err error
+	if rv.Constants, err = b.values(pkg.Doc.Consts); err != nil {
+		return nil, err
+	}
+	if rv.Variables, err = b.values(pkg.Doc.Vars); err != nil {
+		return nil, err
+	}
+	if rv.Functions, err = b.funcs(pkg.Doc.Funcs, ""); err != nil {
+		return nil, err
+	}
+	if rv.Examples, err = b.examples(pkg.Doc.Examples, ""); err != nil {
+		return nil, err
+	}
+
+	for _, t := range pkg.Doc.Types {
+		d, err := b.decl(t.Name, t.Name, t.Decl, t.Doc)
+		if err != nil {
+			return nil, err
+		}
+
+		tctx := &TypeCtx{
+			DeclCtx: *d,
+		}
+		if tctx.Examples, err = b.examples(t.Examples, t.Name); err != nil {
+			return nil, err
+		}
+		if tctx.Constants, err = b.values(t.Consts); err != nil {
+			return nil, err
+		}
+		if tctx.Variables, err = b.values(t.Vars); err != nil {
+			return nil, err
+		}
+		if tctx.Functions, err = b.funcs(t.Funcs, ""); err != nil {
+			return nil, err
+		}
+		if tctx.Methods, err = b.funcs(t.Methods, t.Name); err != nil {
+			return nil, err
+		}
+		rv.Types = append(rv.Types, tctx)
+	}
+	return rv, nil
+}
@@ -0,0 +1,33 @@
+package godocs
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+var (
+	chFormatter = html.New(
+		html.WithClasses(false),
+		html.WithLineNumbers(false),
+	)
+	chLexer = chroma.Coalesce(lexers.Get("go"))
+	chStyle = styles.Get("github")
+)
+
+func highlight(code string) (template.HTML, error) {
+	iter, err := chLexer.Tokenise(nil, code)
+	if err != nil {
+		return "", err
+	}
+
+	b := bytes.Buffer{}
+	if err := chFormatter.Format(&b, chStyle, iter); err != nil {
+		return "", err
+	}
+	return template.HTML(b.String()), nil
+}
@@ -0,0 +1,160 @@
+package godocs
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+type File struct {
+	Name string
+	Data []byte
+}
+
+type Package struct {
+	Dir        string
+	ImportPath string
+	Doc        *doc.Package
+	FileSet    *token.FileSet
+	Files      []string
+}
+
+// skipDir reports if the directory is ignored by the go tool, or holds
+// internal packages, that are only documented if requested.
+func skipDir(dir string, withInternal bool) bool {
+	for part := range strings.SplitSeq(dir, "/") {
+		if part == "." || part == "" {
+			continue
+		}
+		if part[0] == '.' || part[0] == '_' || part == "testdata" || part == "vendor" || (part == "internal" && !withInternal) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsSupported(name string) bool {
+	return path.Ext(name) == ".go" || path.Base(name) == "go.mod"
+}
+
+// Parse documents the packages found in the files. platform-specific files are
+// selected for goos and goarch, that default to linux and amd64.
+func Parse(importPath string, files []*File, withInternal bool, goos string, goarch string) ([]*Package, error) {
+	data := map[string][]byte{}
+	dirs := map[string][]string{}
+	modules := []string{}
+
+	for _, f := range files {
+		dir := path.Dir(f.Name)
+		if path.Base(f.Name) == "go.mod" {
+			if dir != "." {
+				modules = append(modules, dir)
+			}
+			continue
+		}
+		if path.Ext(f.Name) != ".go" || skipDir(dir, withInternal) {
+			continue
+		}
+		data[f.Name] = f.Data
+		dirs[dir] = append(dirs[dir], path.Base(f.Name))
+	}
+
+	ctx := build.Default
+	ctx.GOOS = "linux"
+	if goos != "" {
+		ctx.GOOS = goos
+	}
+	ctx.GOARCH = "amd64"
+	if goarch != "" {
+		ctx.GOARCH = goarch
+	}
+	ctx.CgoEnabled = true
+	ctx.JoinPath = path.Join
+	ctx.IsDir = func(p string) bool {
+		_, found := dirs[p]
+		return found
+	}
+	ctx.OpenFile = func(p string) (io.ReadCloser, error) {
+		d, found := data[p]
+		if !found {
+			return nil, &fs.PathError{Op: "open", Path: p, Err: os.ErrNotExist}
+		}
+		return io.NopCloser(bytes.NewReader(d)), nil
+	}
+
+	rv := []*Package{}
+	for dir, names := range dirs {
+		if slices.ContainsFunc(modules, func(m string) bool {
+			return dir == m || strings.HasPrefix(dir, m+"/")
+		}) {
+			continue
+		}
+
+		slices.Sort(names)
+
+		fset := token.NewFileSet()
+		afiles := []*ast.File{}
+		pfiles := []string{}
+		pkgName := ""
+		for _, name := range names {
+			if match, err := ctx.MatchFile(dir, name); err != nil {
+				return nil, err
+			} else if !match {
+				continue
+			}
+
+			fn := path.Join(dir, name)
+			af, err := parser.ParseFile(fset, fn, data[fn], parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+
+			if !strings.HasSuffix(name, "_test.go") {
+				if pkgName == "" {
+					pkgName = af.Name.Name
+				} else if pkgName != af.Name.Name {
+					return nil, fmt.Errorf("godocs: %s: multiple packages found: %s, %s", dir, pkgName, af.Name.Name)
+				}
+				pfiles = append(pfiles, fn)
+			}
+			afiles = append(afiles, af)
+		}
+
+		if pkgName == "" || pkgName == "main" {
+			continue
+		}
+
+		ip := importPath
+		if dir != "." {
+			ip = path.Join(importPath, dir)
+		}
+
+		dpkg, err := doc.NewFromFiles(fset, afiles, ip)
+		if err != nil {
+			return nil, err
+		}
+
+		rv = append(rv, &Package{
+			Dir:        dir,
+			ImportPath: ip,
+			Doc:        dpkg,
+			FileSet:    fset,
+			Files:      pfiles,
+		})
+	}
+
+	slices.SortFunc(rv, func(a *Package, b *Package) int {
+		return strings.Compare(a.ImportPath, b.ImportPath)
+	})
+	return rv, nil
+}
@@ -0,0 +1,125 @@
+package godocs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	files := []*File{
+		{"go.mod", []byte("module example.com/foo\n")},
+		{"foo.go", []byte("// Package foo does things.\npackage foo\n\nfunc Foo() {}\n")},
+		{"foo_windows.go", []byte("package foo\n\nfunc Windows() {}\n")},
+		{"foo_test.go", []byte("package foo_test\n\nfunc ExampleFoo() {}\n")},
+		{"bar/bar.go", []byte("// Package bar does other things.\npackage bar\n")},
+		{"bar/internal/baz/baz.go", []byte("package baz\n")},
+		{"cmd/foo/main.go", []byte("package main\n")},
+		{"testdata/data.go", []byte("package data\n")},
+		{"_examples/ex.go", []byte("package ex\n")},
+		{"sub/go.mod", []byte("module example.com/foo/sub\n")},
+		{"sub/sub.go", []byte("package sub\n")},
+		{"README.md", []byte("# foo\n")},
+	}
+
+	pkgs, err := Parse("example.com/foo", files, false, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got := []string{}
+	for _, pkg := range pkgs {
+		got = append(got, pkg.ImportPath)
+	}
+	want := []string{"example.com/foo", "example.com/foo/bar"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	foo := pkgs[0]
+	if foo.Dir != "." {
+		t.Errorf("got dir %q, want %q", foo.Dir, ".")
+	}
+	if !slices.Equal(foo.Files, []string{"foo.go"}) {
+		t.Errorf("got files %q, want %q", foo.Files, []string{"foo.go"})
+	}
+	if len(foo.Doc.Funcs) != 1 || foo.Doc.Funcs[0].Name != "Foo" {
+		t.Fatalf("unexpected funcs: %v", foo.Doc.Funcs)
+	}
+	if len(foo.Doc.Funcs[0].Examples) != 1 {
+		t.Errorf("got %d examples, want 1", len(foo.Doc.Funcs[0].Examples))
+	}
+	if s := foo.Doc.Synopsis(foo.Doc.Doc); s != "Package foo does things." {
+		t.Errorf("got synopsis %q", s)
+	}
+}
+
+func TestParseInternal(t *testing.T) {
+	files := []*File{
+		{"foo.go", []byte("package foo\n")},
+		{"internal/bar/bar.go", []byte("package bar\n")},
+		{"vendor/baz/baz.go", []byte("package baz\n")},
+	}
+
+	for _, tt := range []struct {
+		withInternal bool
+		want         []string
+	}{
+		{false, []string{"example.com/foo"}},
+		{true, []string{"example.com/foo", "example.com/foo/internal/bar"}},
+	} {
+		pkgs, err := Parse("example.com/foo", files, tt.withInternal, "", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		got := []string{}
+		for _, pkg := range pkgs {
+			got = append(got, pkg.ImportPath)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("internal=%t: got %q, want %q", tt.withInternal, got, tt.want)
+		}
+	}
+}
+
+func TestParseMultiplePackages(t *testing.T) {
+	files := []*File{
+		{"foo.go", []byte("package foo\n")},
+		{"bar.go", []byte("package bar\n")},
+	}
+
+	if _, err := Parse("example.com/foo", files, false, "", ""); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	files := []*File{
+		{"foo.go", []byte("package foo\n\nfunc Foo() {}\n")},
+		{"foo_windows.go", []byte("package foo\n\nfunc Windows() {}\n")},
+		{"foo_darwin.go", []byte("//go:build darwin\n\npackage foo\n\nfunc Darwin() {}\n")},
+		{"foo_arm64.go", []byte("package foo\n\nfunc Arm64() {}\n")},
+		{"unix.go", []byte("//go:build unix\n\npackage foo\n\nfunc Unix() {}\n")},
+	}
+
+	for _, tt := range []struct {
+		goos   string
+		goarch string
+		want   []string
+	}{
+		{"", "", []string{"foo.go", "unix.go"}},
+		{"windows", "", []string{"foo.go", "foo_windows.go"}},
+		{"darwin", "arm64", []string{"foo.go", "foo_arm64.go", "foo_darwin.go", "unix.go"}},
+	} {
+		pkgs, err := Parse("example.com/foo", files, false, tt.goos, tt.goarch)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(pkgs) != 1 {
+			t.Fatalf("got %d packages, want 1", len(pkgs))
+		}
+		if !slices.Equal(pkgs[0].Files, tt.want) {
+			t.Errorf("%s/%s: got files %q, want %q", tt.goos, tt.goarch, pkgs[0].Files, tt.want)
+		}
+	}
+}
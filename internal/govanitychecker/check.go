@@ -0,0 +1,242 @@
+package govanitychecker
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/config"
+	"rafaelmartins.com/p/website/internal/webserver"
+)
+
+type module struct {
+	importPath string
+	packages   []string
+	vcs        string
+	repoUrl    string
+	sourceUrl  string
+}
+
+func getModules(cfg *config.Config) []*module {
+	rv := []*module{}
+	for _, proj := range cfg.Projects {
+		for _, repo := range proj.Repositories {
+			if repo == nil || repo.Go.Import == "" {
+				continue
+			}
+
+			r := repo.Go.Repo
+			if r == "" {
+				r = repo.Repo
+			}
+			u := "https://github.com/" + repo.Owner + "/" + r
+
+			rv = append(rv, &module{
+				importPath: repo.Go.Import,
+				packages:   repo.Go.Packages,
+				vcs:        "git",
+				repoUrl:    u + ".git",
+				sourceUrl:  u,
+			})
+		}
+	}
+
+	if cfg.GoVanity != nil {
+		for _, mod := range cfg.GoVanity.Modules {
+			if mod == nil || mod.Import == "" {
+				continue
+			}
+
+			src, _, _ := mod.GetSource()
+			rv = append(rv, &module{
+				importPath: mod.Import,
+				packages:   mod.Packages,
+				vcs:        mod.GetVCS(),
+				repoUrl:    mod.Repo,
+				sourceUrl:  src,
+			})
+		}
+	}
+	return rv
+}
+
+type metaTag struct {
+	name   string
+	fields []string
+}
+
+// mimics the parser used by the go command: non-strict xml, stopping at the
+// end of the head.
+func parseMetaTags(r io.Reader) ([]*metaTag, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %q", charset)
+	}
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	rv := []*metaTag{}
+	for {
+		t, err := d.RawToken()
+		if err != nil {
+			if errors.Is(err, io.EOF) || len(rv) > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		if e, ok := t.(xml.StartElement); ok && strings.EqualFold(e.Name.Local, "body") {
+			break
+		}
+		if e, ok := t.(xml.EndElement); ok && strings.EqualFold(e.Name.Local, "head") {
+			break
+		}
+
+		e, ok := t.(xml.StartElement)
+		if !ok || !strings.EqualFold(e.Name.Local, "meta") {
+			continue
+		}
+
+		name, content := "", ""
+		for _, attr := range e.Attr {
+			switch strings.ToLower(attr.Name.Local) {
+			case "name":
+				name = attr.Value
+			case "content":
+				content = attr.Value
+			}
+		}
+		if name == "go-import" || name == "go-source" {
+			rv = append(rv, &metaTag{
+				name:   name,
+				fields: strings.Fields(content),
+			})
+		}
+	}
+	return rv, nil
+}
+
+func checkPath(client *http.Client, baseUrl string, host string, mod *module, importPath string) error {
+	p, found := strings.CutPrefix(importPath, host+"/")
+	if !found {
+		return fmt.Errorf("%s: import path is not served by %s", importPath, host)
+	}
+
+	resp, err := client.Get(baseUrl + "/" + p + "?go-get=1")
+	if err != nil {
+		return fmt.Errorf("%s: %w", importPath, err)
+	}
+	defer resp.Body.Close()
+
+	// like the go command, we ignore the status code if the page includes the meta tags
+	tags, err := parseMetaTags(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", importPath, err)
+	}
+
+	var (
+		imp *metaTag
+		src *metaTag
+	)
+	for _, tag := range tags {
+		if tag.name != "go-import" || len(tag.fields) != 3 {
+			continue
+		}
+		if prefix := tag.fields[0]; importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+			if imp != nil {
+				return fmt.Errorf("%s: multiple go-import meta tags found", importPath)
+			}
+			imp = tag
+		}
+	}
+	if imp == nil {
+		return fmt.Errorf("%s: go-import meta tag not found (HTTP %d)", importPath, resp.StatusCode)
+	}
+	if imp.fields[0] != mod.importPath {
+		return fmt.Errorf("%s: go-import: bad prefix: got %q, want %q", importPath, imp.fields[0], mod.importPath)
+	}
+	if imp.fields[1] != mod.vcs {
+		return fmt.Errorf("%s: go-import: bad vcs: got %q, want %q", importPath, imp.fields[1], mod.vcs)
+	}
+	if imp.fields[2] != mod.repoUrl {
+		return fmt.Errorf("%s: go-import: bad repository: got %q, want %q", importPath, imp.fields[2], mod.repoUrl)
+	}
+
+	if mod.sourceUrl == "" {
+		return nil
+	}
+
+	for _, tag := range tags {
+		if tag.name == "go-source" && len(tag.fields) == 4 && tag.fields[0] == mod.importPath {
+			if src != nil {
+				return fmt.Errorf("%s: multiple go-source meta tags found", importPath)
+			}
+			src = tag
+		}
+	}
+	if src == nil {
+		return fmt.Errorf("%s: go-source meta tag not found", importPath)
+	}
+	if src.fields[1] != mod.sourceUrl {
+		return fmt.Errorf("%s: go-source: bad home: got %q, want %q", importPath, src.fields[1], mod.sourceUrl)
+	}
+	if !strings.Contains(src.fields[2], "{dir}") && !strings.Contains(src.fields[2], "{/dir}") {
+		return fmt.Errorf("%s: go-source: directory template missing {dir}: %q", importPath, src.fields[2])
+	}
+	if !strings.Contains(src.fields[3], "{file}") {
+		return fmt.Errorf("%s: go-source: file template missing {file}: %q", importPath, src.fields[3])
+	}
+	return nil
+}
+
+func Check(cfg *config.Config, dir string) error {
+	mods := getModules(cfg)
+	if len(mods) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return err
+	}
+	host := strings.TrimSuffix(path.Join(u.Host, u.Path), "/")
+
+	srv := httptest.NewServer(webserver.FileServer(dir))
+	defer srv.Close()
+
+	failures := 0
+	for _, mod := range mods {
+		paths := []string{mod.importPath}
+		for _, pkg := range mod.packages {
+			paths = append(paths, path.Join(mod.importPath, pkg))
+		}
+
+		errs := []error{}
+		for _, p := range paths {
+			if err := checkPath(srv.Client(), srv.URL, host, mod, p); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			failures++
+			log.Printf("error: govanitychecker: %s: %s", mod.importPath, errors.Join(errs...))
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("govanitychecker: %d modules failed", failures)
+	}
+	return nil
+}
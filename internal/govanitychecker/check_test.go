@@ -0,0 +1,104 @@
+package govanitychecker
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rafaelmartins.com/p/website/internal/webserver"
+)
+
+const (
+	goImport = `<meta name="go-import" content="example.com/p/foo git https://github.com/bar/foo.git">`
+	goSource = `<meta name="go-source" content="example.com/p/foo https://github.com/bar/foo https://github.com/bar/foo/tree/HEAD{/dir} https://github.com/bar/foo/blob/HEAD{/dir}/{file}#L{line}">`
+)
+
+func TestParseMetaTags(t *testing.T) {
+	tags, err := parseMetaTags(strings.NewReader(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    ` + goImport + `
+    ` + goSource + `
+    <link href="/style.css" rel="stylesheet">
+  </head>
+  <body>
+    <meta name="go-import" content="example.com/p/bar git https://github.com/bar/bar.git">
+  </body>
+</html>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(tags) != 2 {
+		t.Fatalf("got %d tags, want 2", len(tags))
+	}
+	if tags[0].name != "go-import" || len(tags[0].fields) != 3 {
+		t.Errorf("bad go-import tag: %+v", tags[0])
+	}
+	if tags[1].name != "go-source" || len(tags[1].fields) != 4 {
+		t.Errorf("bad go-source tag: %+v", tags[1])
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"p/foo/index.html":       "<html><head>" + goImport + goSource + "</head></html>",
+		"p/foo/bar/index.html":   "<html><head>" + goImport + goSource + "</head></html>",
+		"p/foo/nosrc/index.html": "<html><head>" + goImport + "</head></html>",
+		"404.html":               "<html><head></head><body>not found</body></html>",
+	} {
+		fn := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fn), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv := httptest.NewServer(webserver.FileServer(dir))
+	defer srv.Close()
+
+	mod := &module{
+		importPath: "example.com/p/foo",
+		vcs:        "git",
+		repoUrl:    "https://github.com/bar/foo.git",
+		sourceUrl:  "https://github.com/bar/foo",
+	}
+	badRepo := *mod
+	badRepo.repoUrl = "https://github.com/bar/baz.git"
+
+	tests := []struct {
+		name       string
+		host       string
+		mod        *module
+		importPath string
+		err        string
+	}{
+		{"root", "example.com", mod, "example.com/p/foo", ""},
+		{"sub package", "example.com", mod, "example.com/p/foo/bar", ""},
+		{"missing page", "example.com", mod, "example.com/p/foo/baz", "go-import meta tag not found"},
+		{"missing go-source", "example.com", mod, "example.com/p/foo/nosrc", "go-source meta tag not found"},
+		{"bad repository", "example.com", &badRepo, "example.com/p/foo", "bad repository"},
+		{"other host", "example.org", mod, "example.com/p/foo", "not served by example.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPath(srv.Client(), srv.URL, tt.host, tt.mod, tt.importPath)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -10,8 +10,13 @@ import (
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"rafaelmartins.com/p/website/internal/sanitizer"
 )
 
+// PcSanitize enables the html sanitizer for the rendered document. the value
+// is a name that identifies the document in the logs.
+var PcSanitize = parser.NewContextKey()
+
 func New(style string, ext ...goldmark.Extender) goldmark.Markdown {
 	opt := []highlighting.Option{}
 	if style != "" {
@@ -24,6 +29,7 @@ func New(style string, ext ...goldmark.Extender) goldmark.Markdown {
 				[]goldmark.Extender{
 					&admonitions{},
 					&spoilers{},
+					&references{},
 					&toc{},
 					extension.GFM,
 					extension.DefinitionList,
@@ -54,5 +60,9 @@ func Render(mkd goldmark.Markdown, src []byte, pc parser.Context) (string, strin
 	if err != nil {
 		return "", "", err
 	}
+
+	if name, ok := pc.Get(PcSanitize).(string); ok {
+		return sanitizer.Default.Sanitize(name+" (toc)", t), sanitizer.Default.Sanitize(name, buf.String()), nil
+	}
 	return t, buf.String(), nil
 }
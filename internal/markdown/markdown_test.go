@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestRenderSanitize(t *testing.T) {
+	// everything generated by the markdown extensions must survive the
+	// sanitizer, with the element names namespaced
+	src := strings.Join([]string{
+		"# Title",
+		"",
+		"Some *text* with **bold**, `code`, ~~strike~~, ||spoiler|| and a [link](https://example.com/) :smile:.",
+		"",
+		"> [!NOTE]",
+		"> admonition",
+		"",
+		"- [x] done",
+		"- [ ] todo",
+		"",
+		"| a | b |",
+		"|:--|--:|",
+		"| 1 | 2 |",
+		"",
+		"Term",
+		": definition",
+		"",
+		"![image](img.png)",
+		"Figure caption",
+		"",
+		"```go",
+		"package main",
+		"```",
+		"",
+		"Footnote[^1].",
+		"",
+		"[^1]: the footnote",
+		"",
+	}, "\n")
+
+	gm := New("github")
+
+	pc := parser.NewContext()
+	pc.Set(PcTocEnable, new(true))
+	toc, body, err := Render(gm, []byte(src), pc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pc = parser.NewContext()
+	pc.Set(PcTocEnable, new(true))
+	pc.Set(PcSanitize, "test")
+	stoc, sbody, err := Render(gm, []byte(src), pc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	prefix := strings.NewReplacer(`id="`, `id="user-content-`, `href="#`, `href="#user-content-`)
+	toc = prefix.Replace(toc)
+	body = prefix.Replace(body)
+
+	if stoc != toc {
+		t.Errorf("toc changed by sanitizer:\n%s\n%s", toc, stoc)
+	}
+	if sbody != body {
+		t.Errorf("body changed by sanitizer:\n%s\n%s", body, sbody)
+	}
+}
+
+func TestRenderSanitizeUnsafe(t *testing.T) {
+	src := "<script>alert(1)</script>\n\n<a href=\"javascript:alert(1)\" onclick=\"x()\">link</a>\n"
+
+	pc := parser.NewContext()
+	pc.Set(PcSanitize, "test")
+	_, body, err := Render(New(""), []byte(src), pc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := "\n<p><a>link</a></p>\n"; body != want {
+		t.Errorf("got %q, want %q", body, want)
+	}
+}
@@ -0,0 +1,149 @@
+package markdown
+
+import (
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	PcReferences = parser.NewContextKey()
+
+	referencesPattern = regexp.MustCompile(`([A-Za-z0-9][A-Za-z0-9-]*/[A-Za-z0-9._-]+)?#([0-9]+)|@([A-Za-z0-9][A-Za-z0-9-]{0,38})|([0-9a-f]{7,40})`)
+)
+
+const referencesBaseUrl = "https://github.com"
+
+// References enables github-style autolinking of issues, pull requests,
+// commits and user mentions, relative to the given repository.
+type References struct {
+	Owner string
+	Repo  string
+}
+
+func isReferenceWordByte(b byte) bool {
+	return b == '_' || b == '-' || b == '/' || b == '&' || b == '@' || b == '#' || util.IsAlphaNumeric(b)
+}
+
+func (r *References) match(source []byte, m []int) (string, int, bool) {
+	if m[0] > 0 && isReferenceWordByte(source[m[0]-1]) {
+		return "", 0, false
+	}
+	if m[1] < len(source) && (isReferenceWordByte(source[m[1]]) || source[m[1]] == '.' && m[1]+1 < len(source) && util.IsAlphaNumeric(source[m[1]+1])) {
+		return "", 0, false
+	}
+
+	switch {
+	case m[4] >= 0:
+		repo := r.Owner + "/" + r.Repo
+		if m[2] >= 0 {
+			repo = string(source[m[2]:m[3]])
+		}
+		return referencesBaseUrl + "/" + repo + "/issues/" + string(source[m[4]:m[5]]), m[1], true
+
+	case m[6] >= 0:
+		return referencesBaseUrl + "/" + string(source[m[6]:m[7]]), m[1], true
+
+	case m[8] >= 0:
+		// hexadecimal words are too common, require both letters and digits
+		sha := source[m[8]:m[9]]
+		letters, digits := false, false
+		for _, c := range sha {
+			if c >= 'a' && c <= 'f' {
+				letters = true
+			} else {
+				digits = true
+			}
+		}
+		if !letters || !digits {
+			return "", 0, false
+		}
+
+		// commits are displayed shortened, like github does
+		return referencesBaseUrl + "/" + r.Owner + "/" + r.Repo + "/commit/" + string(sha), m[8] + 7, true
+	}
+	return "", 0, false
+}
+
+func (r *References) replace(node *ast.Text, source []byte) {
+	seg := node.Segment
+	value := seg.Value(source)
+	parent := node.Parent()
+
+	var last ast.Node = node
+	cur := 0
+	for _, m := range referencesPattern.FindAllSubmatchIndex(value, -1) {
+		u, stop, ok := r.match(value, m)
+		if !ok {
+			continue
+		}
+
+		if m[0] > cur {
+			t := ast.NewTextSegment(text.NewSegment(seg.Start+cur, seg.Start+m[0]))
+			parent.InsertAfter(parent, last, t)
+			last = t
+		}
+
+		link := ast.NewLink()
+		link.Destination = []byte(u)
+		link.AppendChild(link, ast.NewTextSegment(text.NewSegment(seg.Start+m[0], seg.Start+stop)))
+		parent.InsertAfter(parent, last, link)
+		last = link
+		cur = m[1]
+	}
+
+	if last == node {
+		return
+	}
+
+	t := ast.NewTextSegment(text.NewSegment(seg.Start+cur, seg.Stop))
+	t.SetSoftLineBreak(node.SoftLineBreak())
+	t.SetHardLineBreak(node.HardLineBreak())
+	parent.InsertAfter(parent, last, t)
+	parent.RemoveChild(parent, node)
+}
+
+type referencesTransformer struct{}
+
+func (t *referencesTransformer) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	refs, ok := pc.Get(PcReferences).(*References)
+	if !ok || refs == nil || refs.Owner == "" || refs.Repo == "" {
+		return
+	}
+
+	nodes := []*ast.Text{}
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.Kind() {
+		case ast.KindLink, ast.KindAutoLink, ast.KindImage, ast.KindCodeSpan, ast.KindRawHTML, ast.KindHTMLBlock:
+			return ast.WalkSkipChildren, nil
+
+		case ast.KindText:
+			if t := n.(*ast.Text); !t.IsRaw() {
+				nodes = append(nodes, t)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range nodes {
+		refs.replace(n, reader.Source())
+	}
+}
+
+type references struct{}
+
+func (e *references) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(&referencesTransformer{}, 100),
+		),
+	)
+}
@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "issue",
+			input:    `Fixed in #123.`,
+			expected: "<p>Fixed in <a href=\"https://github.com/foo/bar/issues/123\">#123</a>.</p>\n",
+		},
+		{
+			name:     "issue from other repository",
+			input:    `See baz/qux#45 for details.`,
+			expected: "<p>See <a href=\"https://github.com/baz/qux/issues/45\">baz/qux#45</a> for details.</p>\n",
+		},
+		{
+			name:     "mention",
+			input:    `Thanks @octo-cat!`,
+			expected: "<p>Thanks <a href=\"https://github.com/octo-cat\">@octo-cat</a>!</p>\n",
+		},
+		{
+			name:     "commit",
+			input:    `Reverts a1b2c3d4e5f6a7b8c9d0.`,
+			expected: "<p>Reverts <a href=\"https://github.com/foo/bar/commit/a1b2c3d4e5f6a7b8c9d0\">a1b2c3d</a>.</p>\n",
+		},
+		{
+			name:     "multiple references across lines",
+			input:    "#1 and #2\nby @foo",
+			expected: "<p><a href=\"https://github.com/foo/bar/issues/1\">#1</a> and <a href=\"https://github.com/foo/bar/issues/2\">#2</a>\nby <a href=\"https://github.com/foo\">@foo</a></p>\n",
+		},
+		{
+			name:     "email is not a mention",
+			input:    `Mail foo@example`,
+			expected: "<p>Mail foo@example</p>\n",
+		},
+		{
+			name:     "hexadecimal word is not a commit",
+			input:    `The value is deadbeef or 12345678.`,
+			expected: "<p>The value is deadbeef or 12345678.</p>\n",
+		},
+		{
+			name:     "issue inside word is ignored",
+			input:    `abc#123 and &#123;`,
+			expected: "<p>abc#123 and {</p>\n",
+		},
+		{
+			name:     "code span is ignored",
+			input:    "`#123` and `@foo`",
+			expected: "<p><code>#123</code> and <code>@foo</code></p>\n",
+		},
+		{
+			name:     "link text is ignored",
+			input:    `[#123](https://example.com)`,
+			expected: "<p><a href=\"https://example.com\">#123</a></p>\n",
+		},
+	}
+
+	md := New("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := parser.NewContext()
+			pc.Set(PcReferences, &References{Owner: "foo", Repo: "bar"})
+
+			buf := &bytes.Buffer{}
+			if err := md.Convert([]byte(tt.input), buf, parser.WithContext(pc)); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReferencesDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := New("").Convert([]byte(`#123 by @foo`), buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := buf.String(), "<p>#123 by @foo</p>\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package opengraph
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+type FontConfig struct {
+	GithubOwner *string `yaml:"github-owner"`
+	GithubRepo  *string `yaml:"github-repo"`
+	GithubFile  *string `yaml:"github-file"`
+	GithubRef   *string `yaml:"github-ref"`
+}
+
+type fontCacheEntry struct {
+	font *sfnt.Font
+	err  error
+	once sync.Once
+}
+
+var (
+	fontCache   = map[string]*fontCacheEntry{}
+	fontCacheMu sync.Mutex
+)
+
+// fonts are fetched from github once, and shared between all the users.
+func loadFont(config *FontConfig, defaultFile string) (*sfnt.Font, error) {
+	owner := fontGithubOwner
+	repo := fontGithubRepo
+	file := defaultFile
+	ref := fontGithubRef
+	if config != nil {
+		if config.GithubOwner != nil {
+			owner = *config.GithubOwner
+		}
+		if config.GithubRepo != nil {
+			repo = *config.GithubRepo
+		}
+		if config.GithubFile != nil {
+			file = *config.GithubFile
+		}
+		if config.GithubRef != nil {
+			ref = *config.GithubRef
+		}
+	}
+
+	key := strings.Join([]string{owner, repo, file, ref}, "/")
+
+	fontCacheMu.Lock()
+	entry, found := fontCache[key]
+	if !found {
+		entry = &fontCacheEntry{}
+		fontCache[key] = entry
+	}
+	fontCacheMu.Unlock()
+
+	entry.once.Do(func() {
+		resp, err := github.GetRepositoryFile(owner, repo, file, ref)
+		if err != nil {
+			entry.err = err
+			return
+		}
+		defer resp.Close()
+
+		fontBytes, err := io.ReadAll(resp)
+		if err != nil {
+			entry.err = err
+			return
+		}
+
+		entry.font, entry.err = opentype.Parse(fontBytes)
+	})
+	return entry.font, entry.err
+}
+
+// TextMeasurer measures the width of strings rendered with a font, in pixels.
+type TextMeasurer struct {
+	config      *FontConfig
+	defaultFile string
+	size        float64
+
+	face font.Face
+	err  error
+	once sync.Once
+	mtx  sync.Mutex
+}
+
+// NewTextMeasurer creates a text measurer for the given font. The font is only
+// fetched when measuring the first string.
+func NewTextMeasurer(config *FontConfig, defaultFile string, size float64) *TextMeasurer {
+	if defaultFile == "" {
+		defaultFile = fontGithubFile
+	}
+	return &TextMeasurer{
+		config:      config,
+		defaultFile: defaultFile,
+		size:        size,
+	}
+}
+
+func (m *TextMeasurer) Measure(text string) (float64, error) {
+	m.once.Do(func() {
+		f, err := loadFont(m.config, m.defaultFile)
+		if err != nil {
+			m.err = err
+			return
+		}
+
+		m.face, m.err = opentype.NewFace(f, &opentype.FaceOptions{
+			Size:    m.size,
+			DPI:     72,
+			Hinting: font.HintingNone,
+		})
+	})
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	// font faces are not safe for concurrent use
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return float64(font.MeasureString(m.face, text)) / 64, nil
+}
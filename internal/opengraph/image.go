@@ -16,7 +16,6 @@ import (
 	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
-	"rafaelmartins.com/p/website/internal/github"
 	"rafaelmartins.com/p/website/internal/hexcolor"
 	"rafaelmartins.com/p/website/internal/xcf"
 )
@@ -29,16 +28,11 @@ const (
 )
 
 type ImageGenConfig struct {
-	Template string `yaml:"template"`
-	Font     struct {
-		GithubOwner *string `yaml:"github-owner"`
-		GithubRepo  *string `yaml:"github-repo"`
-		GithubFile  *string `yaml:"github-file"`
-		GithubRef   *string `yaml:"github-ref"`
-	} `yaml:"font"`
-	DefaultColor *string  `yaml:"default-color"`
-	DefaultDPI   *float64 `yaml:"default-dpi"`
-	DefaultSize  *float64 `yaml:"default-size"`
+	Template     string     `yaml:"template"`
+	Font         FontConfig `yaml:"font"`
+	DefaultColor *string    `yaml:"default-color"`
+	DefaultDPI   *float64   `yaml:"default-dpi"`
+	DefaultSize  *float64   `yaml:"default-size"`
 }
 
 type OpenGraphImageGen struct {
@@ -81,35 +75,8 @@ func NewImageGen(config *ImageGenConfig) (*OpenGraphImageGen, error) {
 		rv.dsize = *config.DefaultSize
 	}
 
-	owner := fontGithubOwner
-	repo := fontGithubRepo
-	file := fontGithubFile
-	ref := fontGithubRef
-	if config.Font.GithubOwner != nil {
-		owner = *config.Font.GithubOwner
-	}
-	if config.Font.GithubRepo != nil {
-		repo = *config.Font.GithubRepo
-	}
-	if config.Font.GithubFile != nil {
-		file = *config.Font.GithubFile
-	}
-	if config.Font.GithubRef != nil {
-		ref = *config.Font.GithubRef
-	}
-
-	resp, err := github.GetRepositoryFile(owner, repo, file, ref)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Close()
-
-	fontBytes, err := io.ReadAll(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	rv.font, err = opentype.Parse(fontBytes)
+	var err error
+	rv.font, err = loadFont(&config.Font, fontGithubFile)
 	if err != nil {
 		return nil, err
 	}
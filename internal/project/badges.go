@@ -0,0 +1,149 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+var reBadgeName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+type ProjectBadge struct {
+	Name  string
+	Label string
+	Value string
+	Color string
+}
+
+type projectBadge struct {
+	proj  *Project
+	badge *ProjectBadge
+}
+
+func (b *projectBadge) GetDestination() string {
+	return filepath.Join(b.proj.slug(), b.proj.badgesDestination, b.badge.Name+".svg")
+}
+
+func (b *projectBadge) GetGenerator() (runner.Generator, error) {
+	return b, nil
+}
+
+func (*projectBadge) GetID() string {
+	return "BADGE"
+}
+
+func (b *projectBadge) GetReader() (io.ReadCloser, error) {
+	data, err := b.proj.BadgeRenderer.Render(b.badge.Label, b.badge.Value, b.badge.Color)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b *projectBadge) GetPaths() ([]string, error) {
+	return b.proj.statePaths(b.badge.Name == "stars"), nil
+}
+
+func (b *projectBadge) GetImmutable() bool {
+	return b.proj.immutable()
+}
+
+func (*projectBadge) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
+
+func (p *Project) initBadges() error {
+	p.badgesDestination = p.BadgesDestination
+	if p.badgesDestination == "" {
+		p.badgesDestination = "badges"
+	}
+
+	p.badgesUrl = ""
+	p.badges = nil
+	if !p.BadgesEnabled {
+		return nil
+	}
+	p.badgesUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.badgesDestination) + "/"
+
+	if p.proj.LatestRelease != nil {
+		p.badges = append(p.badges, &ProjectBadge{
+			Name:  "version",
+			Label: "version",
+			Value: p.proj.LatestRelease.Tag,
+			Color: "blue",
+		})
+	}
+	if p.license != "" {
+		p.badges = append(p.badges, &ProjectBadge{
+			Name:  "license",
+			Label: "license",
+			Value: p.license,
+			Color: "blue",
+		})
+	}
+	p.badges = append(p.badges, &ProjectBadge{
+		Name:  "stars",
+		Label: "stars",
+		Value: strconv.Itoa(p.proj.Stars),
+		Color: "lightgrey",
+	})
+
+	// custom badges can replace the default ones
+	for _, b := range p.BadgesCustom {
+		if b == nil {
+			continue
+		}
+		if !reBadgeName.MatchString(b.Name) {
+			return fmt.Errorf("project: badges: invalid name: %q", b.Name)
+		}
+		if b.Value == "" {
+			return fmt.Errorf("project: badges: %s: empty value", b.Name)
+		}
+
+		found := false
+		for i, bb := range p.badges {
+			if bb.Name == b.Name {
+				p.badges[i] = b
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.badges = append(p.badges, b)
+		}
+	}
+	return nil
+}
+
+func (p *Project) getBadgesTemplateEntries() []*templates.ProjectContentBadge {
+	rv := []*templates.ProjectContentBadge{}
+	for _, b := range p.badges {
+		rv = append(rv, &templates.ProjectContentBadge{
+			Name:  b.Name,
+			Label: b.Label,
+			Value: b.Value,
+			URL:   path.Join(p.badgesUrl, b.Name+".svg"),
+		})
+	}
+	return rv
+}
+
+func (p *Project) getBadgesTasks() []*runner.Task {
+	rv := []*runner.Task{}
+	for _, b := range p.badges {
+		rv = append(rv, runner.NewTask(p, &projectBadge{
+			proj:  p,
+			badge: b,
+		}))
+	}
+	return rv
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"testing"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func TestInitBadges(t *testing.T) {
+	proj := &Project{
+		Repo:          "foo",
+		BadgesEnabled: true,
+		BadgesCustom: []*ProjectBadge{
+			{Name: "stars", Label: "stargazers", Value: "many"},
+			{Name: "build", Label: "build", Value: "passing", Color: "brightgreen"},
+		},
+		license: "BSD-3-Clause",
+		proj: &github.Repository{
+			Stars: 42,
+			LatestRelease: &github.RepositoryLatestRelease{
+				Tag: "v1.2.3",
+			},
+		},
+	}
+
+	if err := proj.initBadges(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []struct {
+		name  string
+		value string
+		url   string
+	}{
+		{"version", "v1.2.3", "/projects/foo/badges/version.svg"},
+		{"license", "BSD-3-Clause", "/projects/foo/badges/license.svg"},
+		{"stars", "many", "/projects/foo/badges/stars.svg"},
+		{"build", "passing", "/projects/foo/badges/build.svg"},
+	}
+
+	entries := proj.getBadgesTemplateEntries()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d badges, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		if entries[i].Name != w.name || entries[i].Value != w.value || entries[i].URL != w.url {
+			t.Errorf("badge %d: got %+v, want %+v", i, entries[i], w)
+		}
+	}
+
+	if tasks := proj.getBadgesTasks(); len(tasks) != len(want) {
+		t.Errorf("got %d tasks, want %d", len(tasks), len(want))
+	}
+}
+
+func TestInitBadgesDisabled(t *testing.T) {
+	proj := &Project{
+		Repo: "foo",
+		proj: &github.Repository{},
+	}
+	if err := proj.initBadges(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if proj.badgesUrl != "" || len(proj.badges) != 0 {
+		t.Errorf("badges should be disabled")
+	}
+}
+
+func TestInitBadgesInvalid(t *testing.T) {
+	for _, b := range []*ProjectBadge{
+		{Name: "../foo", Value: "bar"},
+		{Name: "", Value: "bar"},
+		{Name: "foo", Value: ""},
+	} {
+		proj := &Project{
+			Repo:          "foo",
+			BadgesEnabled: true,
+			BadgesCustom:  []*ProjectBadge{b},
+			proj:          &github.Repository{},
+		}
+		if err := proj.initBadges(); err == nil {
+			t.Errorf("expected error for %+v", b)
+		}
+	}
+}
@@ -20,7 +20,7 @@ type cDocs struct {
 }
 
 func (c *cDocs) GetDestination() string {
-	return filepath.Join(c.proj.Repo, c.proj.cdocsDestination, "index.html")
+	return filepath.Join(c.proj.slug(), c.proj.cdocsDestination, "index.html")
 }
 
 func (c *cDocs) GetGenerator() (runner.Generator, error) {
@@ -40,7 +40,7 @@ func (c *cDocs) getTemplate() string {
 }
 
 func (c *cDocs) GetReader() (io.ReadCloser, error) {
-	baseHtmlUrl := "https://github.com/" + c.proj.Owner + "/" + c.proj.Repo + "/blob/" + c.proj.proj.Head
+	baseHtmlUrl := c.proj.blobUrl(c.proj.proj.Head)
 	headerPath := ""
 	if c.proj.CDocsBaseDirectory != nil {
 		baseHtmlUrl += "/" + *c.proj.CDocsBaseDirectory
@@ -82,7 +82,7 @@ func (c *cDocs) GetReader() (io.ReadCloser, error) {
 		return nil, err
 	}
 
-	title := fmt.Sprintf("%s: API Documentation", c.proj.Repo)
+	title := fmt.Sprintf("%s: API Documentation", c.proj.slug())
 
 	og, err := opengraph.New(c.proj.OpenGraphImageGen, false, c.proj.cdocsUrl, title, "", c.proj.CDocsOpenGraph, "", "", nil)
 	if err != nil {
@@ -108,7 +108,7 @@ func (c *cDocs) GetReader() (io.ReadCloser, error) {
 }
 
 func (c *cDocs) GetPaths() ([]string, error) {
-	if c.proj.Immutable && c.proj.LocalDirectory == nil {
+	if c.proj.immutable() {
 		return nil, nil
 	}
 
@@ -119,18 +119,19 @@ func (c *cDocs) GetPaths() ([]string, error) {
 
 	if c.proj.LocalDirectory != nil {
 		for _, header := range c.proj.proj.Headers {
-			rv = append(rv, filepath.Join(*c.proj.LocalDirectory, header.Name))
+			rv = append(rv, c.proj.localPath(header.Name))
 		}
 	}
 
 	if c.proj.OpenGraphImageGen != nil {
 		rv = append(rv, c.proj.OpenGraphImageGen.GetPaths()...)
 	}
+	rv = append(rv, c.proj.statePaths(false)...)
 	return rv, nil
 }
 
 func (c *cDocs) GetImmutable() bool {
-	return c.proj.Immutable && c.proj.LocalDirectory == nil
+	return c.proj.immutable()
 }
 
 func (c *cDocs) GetByProducts(ch chan *runner.GeneratorByProduct) {
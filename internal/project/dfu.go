@@ -32,7 +32,7 @@ type dfu struct {
 }
 
 func (d *dfu) GetDestination() string {
-	return filepath.Join(d.proj.Repo, d.proj.dfuDestination, "index.json")
+	return filepath.Join(d.proj.slug(), d.proj.dfuDestination, "index.json")
 }
 
 func (d *dfu) GetGenerator() (runner.Generator, error) {
@@ -121,7 +121,7 @@ func (d *dfu) GetReader() (io.ReadCloser, error) {
 	}
 
 	v := &dfuOut{
-		Name: d.proj.Repo,
+		Name: d.proj.slug(),
 	}
 	for _, file := range d.files {
 		u, err := url.JoinPath(d.proj.dfuUrl, file.name+"-"+file.version+".json")
@@ -145,11 +145,11 @@ func (d *dfu) GetReader() (io.ReadCloser, error) {
 }
 
 func (d *dfu) GetPaths() ([]string, error) {
-	return nil, nil
+	return d.proj.statePaths(false), nil
 }
 
 func (d *dfu) GetImmutable() bool {
-	return d.proj.Immutable
+	return d.proj.Immutable && d.proj.TTL <= 0
 }
 
 func (d *dfu) GetByProducts(ch chan *runner.GeneratorByProduct) {
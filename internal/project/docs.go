@@ -0,0 +1,329 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/frontmatter"
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+var reSummaryEntry = regexp.MustCompile(`^(\s*)(?:[-*+]\s+)?\[(.*)\]\((.*)\)\s*$`)
+
+type projectDoc struct {
+	file     *github.RepositoryFile
+	idx      int
+	weight   int
+	weighted bool
+	name     string
+	isIndex  bool
+	isReadme bool
+	isRoot   bool
+}
+
+type projectNav struct {
+	title    string
+	weight   int
+	doc      *projectDoc
+	page     *ProjectPage
+	children []*projectNav
+}
+
+func splitPrefix(s string) (int, string, bool) {
+	p, name, found := strings.Cut(s, "_")
+	if !found {
+		return 0, s, false
+	}
+
+	i, err := strconv.ParseInt(p, 10, 32)
+	if err != nil {
+		return 0, s, false
+	}
+	return int(i), name, true
+}
+
+func isIndexFileName(fileName string) bool {
+	_, name, _ := splitPrefix(path.Base(fileName))
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return name == "index" || name == "README"
+}
+
+func walkNav(nav []*projectNav, f func(n *projectNav, parents []*projectNav) bool) {
+	var walk func(nav []*projectNav, parents []*projectNav) bool
+	walk = func(nav []*projectNav, parents []*projectNav) bool {
+		for _, n := range nav {
+			if !f(n, parents) {
+				return false
+			}
+			if !walk(n.children, append(slices.Clip(parents), n)) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(nav, nil)
+}
+
+func newDocs(files []*github.RepositoryFile, dir string) ([]*projectDoc, []*projectNav, error) {
+	var summary *github.RepositoryFile
+	docs := []*projectDoc{}
+	for _, file := range files {
+		if file.Name == path.Join(dir, "SUMMARY.md") {
+			summary = file
+			continue
+		}
+
+		data, err := file.Read()
+		if err != nil {
+			return nil, nil, err
+		}
+		meta, _, err := frontmatter.Parse(data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		idx, _, _ := splitPrefix(path.Base(file.Name))
+		doc := &projectDoc{
+			file:    file,
+			idx:     idx,
+			weight:  idx,
+			isIndex: isIndexFileName(file.Name),
+		}
+		if meta.Weight != nil {
+			doc.weight = *meta.Weight
+			doc.weighted = true
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, nil, fmt.Errorf("project: no documentation pages found")
+	}
+
+	var nav []*projectNav
+	if summary == nil {
+		nav = newTreeNav(dir, docs)
+	} else {
+		data, err := summary.Read()
+		if err != nil {
+			return nil, nil, err
+		}
+		nav, err = newSummaryNav(data, dir, docs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := setDocsNames(dir, docs, nav); err != nil {
+		return nil, nil, err
+	}
+	return docs, nav, nil
+}
+
+func setDocsNames(dir string, docs []*projectDoc, nav []*projectNav) error {
+	// the root page is the index of the documentation directory, or the first one
+	var root *projectDoc
+	walkNav(nav, func(n *projectNav, parents []*projectNav) bool {
+		if n.doc != nil {
+			root = n.doc
+			return false
+		}
+		return true
+	})
+	if root == nil {
+		return fmt.Errorf("project: no root page found")
+	}
+	root.isRoot = true
+
+	names := map[string]*projectDoc{}
+	for _, doc := range docs {
+		if err := doc.setName(dir); err != nil {
+			return err
+		}
+		if other, found := names[doc.name]; found {
+			return fmt.Errorf("project: page: %s and %s resolve to the same url", other.file.Name, doc.file.Name)
+		}
+		names[doc.name] = doc
+	}
+	return nil
+}
+
+func (d *projectDoc) setName(dir string) error {
+	rel := strings.TrimPrefix(d.file.Name, dir+"/")
+
+	rv := []string{}
+	for seg := range strings.SplitSeq(path.Dir(rel), "/") {
+		if seg == "." {
+			continue
+		}
+		_, name, _ := splitPrefix(seg)
+		rv = append(rv, name)
+	}
+
+	if !d.isIndex || d.isRoot {
+		_, name, err := splitFileName(rel, d.isReadme, d.isRoot)
+		if err != nil {
+			return err
+		}
+		if d.isRoot {
+			d.name = name
+			return nil
+		}
+		rv = append(rv, name)
+	}
+	d.name = path.Join(rv...)
+	if d.name == "." {
+		d.name = ""
+	}
+	return nil
+}
+
+func sortNav(nav []*projectNav) {
+	slices.SortStableFunc(nav, func(a *projectNav, b *projectNav) int {
+		return a.weight - b.weight
+	})
+	for _, n := range nav {
+		sortNav(n.children)
+	}
+}
+
+func newTreeNav(dir string, docs []*projectDoc) []*projectNav {
+	root := &projectNav{}
+	sections := map[string]*projectNav{".": root}
+
+	var section func(d string) *projectNav
+	section = func(d string) *projectNav {
+		if rv, found := sections[d]; found {
+			return rv
+		}
+
+		// sections are sorted by the prefix of the directory name, unless
+		// the index page defines a weight.
+		idx, name, _ := splitPrefix(path.Base(d))
+		rv := &projectNav{
+			title:  name,
+			weight: idx,
+		}
+		parent := section(path.Dir(d))
+		parent.children = append(parent.children, rv)
+		sections[d] = rv
+		return rv
+	}
+
+	var index *projectNav
+	for _, doc := range docs {
+		d := path.Dir(strings.TrimPrefix(doc.file.Name, dir+"/"))
+		n := &projectNav{
+			doc:    doc,
+			weight: doc.weight,
+		}
+
+		if doc.isIndex {
+			if d == "." {
+				index = n
+				continue
+			}
+
+			s := section(d)
+			s.title = ""
+			s.doc = doc
+			if doc.weighted {
+				s.weight = doc.weight
+			}
+			continue
+		}
+
+		s := section(d)
+		s.children = append(s.children, n)
+	}
+
+	sortNav(root.children)
+	groupNav(root)
+
+	// the index of the documentation directory is always the first page
+	if index != nil {
+		return append([]*projectNav{index}, root.children...)
+	}
+	return root.children
+}
+
+// pages with indexes that are not multiples of 10 are grouped under the
+// previous multiple of 10, if any. this is how subpages were implemented
+// before nested directories were supported.
+func groupNav(nav *projectNav) {
+	rv := []*projectNav{}
+	var parent *projectNav
+	for _, n := range nav.children {
+		groupNav(n)
+
+		if n.doc == nil || n.doc.isIndex || len(n.children) > 0 {
+			rv = append(rv, n)
+			parent = nil
+			continue
+		}
+
+		if n.doc.idx%10 == 0 {
+			rv = append(rv, n)
+			parent = n
+			continue
+		}
+
+		if parent != nil && parent.doc.idx == n.doc.idx-n.doc.idx%10 {
+			parent.children = append(parent.children, n)
+			continue
+		}
+		rv = append(rv, n)
+	}
+	nav.children = rv
+}
+
+// mdbook-style SUMMARY.md: nested lists of links to the pages. links without
+// destination are rendered as sections.
+func newSummaryNav(data []byte, dir string, docs []*projectDoc) ([]*projectNav, error) {
+	files := map[string]*projectDoc{}
+	for _, doc := range docs {
+		files[doc.file.Name] = doc
+	}
+
+	type level struct {
+		indent int
+		nav    *projectNav
+	}
+	root := &projectNav{}
+	stack := []*level{{indent: -1, nav: root}}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		m := reSummaryEntry.FindStringSubmatch(strings.ReplaceAll(s.Text(), "\t", "    "))
+		if m == nil {
+			continue
+		}
+
+		n := &projectNav{title: m[2]}
+		if m[3] != "" {
+			src := path.Join(dir, m[3])
+			doc, found := files[src]
+			if !found {
+				return nil, fmt.Errorf("project: summary: page not found: %s", m[3])
+			}
+			n.doc = doc
+		}
+
+		indent := len(m[1])
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].nav
+		parent.children = append(parent.children, n)
+		stack = append(stack, &level{indent: indent, nav: n})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return root.children, nil
+}
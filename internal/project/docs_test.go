@@ -0,0 +1,158 @@
+package project
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func newTestDocs(names ...string) []*projectDoc {
+	rv := []*projectDoc{}
+	for _, name := range names {
+		idx, _, _ := splitPrefix(name[strings.LastIndex(name, "/")+1:])
+		rv = append(rv, &projectDoc{
+			file:    &github.RepositoryFile{Name: name},
+			idx:     idx,
+			weight:  idx,
+			isIndex: isIndexFileName(name),
+		})
+	}
+	return rv
+}
+
+func dumpNav(nav []*projectNav) string {
+	rv := []string{}
+	walkNav(nav, func(n *projectNav, parents []*projectNav) bool {
+		v := strings.Repeat("-", len(parents))
+		if n.doc != nil {
+			v += n.doc.name
+		} else {
+			v += "[" + n.title + "]"
+		}
+		rv = append(rv, v)
+		return true
+	})
+	return strings.Join(rv, " ")
+}
+
+func TestTreeNav(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			"flat",
+			[]string{"docs/10_intro.md", "docs/20_usage.md", "docs/30_faq.md"},
+			" usage faq",
+		},
+		{
+			"flat with subpages",
+			[]string{"docs/10_intro.md", "docs/20_usage.md", "docs/21_advanced.md", "docs/22_expert.md", "docs/30_faq.md"},
+			" usage -advanced -expert faq",
+		},
+		{
+			"index",
+			[]string{"docs/about.md", "docs/index.md", "docs/install.md"},
+			" about install",
+		},
+		{
+			"nested",
+			[]string{
+				"docs/10_intro.md",
+				"docs/30_software/10_cli.md",
+				"docs/30_software/20_library.md",
+				"docs/20_hardware/index.md",
+				"docs/20_hardware/20_pcb.md",
+				"docs/20_hardware/10_board/10_pinout.md",
+			},
+			" hardware -[board] --hardware/board/pinout -hardware/pcb [software] -software/cli -software/library",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := newTestDocs(tt.files...)
+			nav := newTreeNav("docs", docs)
+			if err := setDocsNames("docs", docs, nav); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := dumpNav(nav); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTreeNavWeight(t *testing.T) {
+	docs := newTestDocs("docs/a.md", "docs/b.md", "docs/c.md")
+	docs[0].weight, docs[0].weighted = 30, true
+	docs[2].weight, docs[2].weighted = 10, true
+
+	nav := newTreeNav("docs", docs)
+	if err := setDocsNames("docs", docs, nav); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := dumpNav(nav); got != " c a" {
+		t.Errorf("got %q, want %q", got, " c a")
+	}
+	if !docs[1].isRoot || docs[1].name != "" {
+		t.Errorf("unexpected root page: %+v", docs[1])
+	}
+}
+
+func TestTreeNavConflict(t *testing.T) {
+	docs := newTestDocs("docs/10_intro.md", "docs/20_usage.md", "docs/30_usage.markdown")
+	if err := setDocsNames("docs", docs, newTreeNav("docs", docs)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSummaryNav(t *testing.T) {
+	docs := newTestDocs("docs/README.md", "docs/install.md", "docs/hardware/board.md", "docs/hardware/pcb.md", "docs/extra.md")
+	nav, err := newSummaryNav([]byte(`# Summary
+
+[Introduction](README.md)
+
+- [Installation](install.md)
+- [Hardware]()
+    - [Board](hardware/board.md)
+    - [PCB](./hardware/pcb.md)
+
+---
+
+- [Unlisted]()
+`), "docs", docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := setDocsNames("docs", docs, nav); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got, want := dumpNav(nav), " install [Hardware] -hardware/board -hardware/pcb [Unlisted]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	titles := []string{}
+	walkNav(nav, func(n *projectNav, parents []*projectNav) bool {
+		titles = append(titles, n.title)
+		return true
+	})
+	if want := []string{"Introduction", "Installation", "Hardware", "Board", "PCB", "Unlisted"}; !slices.Equal(titles, want) {
+		t.Errorf("got titles %q, want %q", titles, want)
+	}
+	if docs[4].name != "extra" {
+		t.Errorf("got unlisted page name %q, want %q", docs[4].name, "extra")
+	}
+}
+
+func TestSummaryNavMissingPage(t *testing.T) {
+	docs := newTestDocs("docs/README.md")
+	if _, err := newSummaryNav([]byte("- [Missing](missing.md)\n"), "docs", docs); err == nil {
+		t.Fatal("expected error")
+	}
+}
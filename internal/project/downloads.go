@@ -0,0 +1,406 @@
+package project
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+var downloadsSignatureExtensions = []string{".asc", ".sig", ".minisig"}
+
+// signature files are listed next to the asset they sign, if it exists.
+func downloadsSignatureOf(name string) (string, bool) {
+	for _, ext := range downloadsSignatureExtensions {
+		if rv, found := strings.CutSuffix(name, ext); found && rv != "" {
+			return rv, true
+		}
+	}
+	return "", false
+}
+
+func downloadsChecksumsLine(sum string, name string) string {
+	return sum + "  " + name + "\n"
+}
+
+// checksums are computed from the mirrored assets, that are generated by
+// other tasks, so that each asset is downloaded just once.
+func getDownloadsAssetChecksum(fpath string) (string, string, error) {
+	fp, err := os.Open(fpath)
+	if err != nil {
+		return "", "", err
+	}
+	defer fp.Close()
+
+	s256 := sha256.New()
+	s512 := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(s256, s512), fp); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(s256.Sum(nil)), hex.EncodeToString(s512.Sum(nil)), nil
+}
+
+func (p *Project) getDownloadsReleases() []*github.RepositoryRelease {
+	rv := p.proj.Releases
+	if p.DownloadsReleases > 0 && len(rv) > p.DownloadsReleases {
+		rv = rv[:p.DownloadsReleases]
+	}
+	return rv
+}
+
+func (p *Project) isDownloadsMirrored(release *github.RepositoryRelease, asset *github.RepositoryReleaseAsset) bool {
+	if p.downloadsUrl == "" || p.downloadsMirrorPattern == nil {
+		return false
+	}
+	if !slices.Contains(p.getDownloadsReleases(), release) {
+		return false
+	}
+	return p.downloadsMirrorPattern.MatchString(asset.Name)
+}
+
+func (p *Project) getDownloadsReleaseUrl(tag string) string {
+	return path.Join(p.downloadsUrl, tag) + "/"
+}
+
+// getAssetUrl returns the url of the local mirror of a release asset, if
+// mirrored, or its original download url otherwise.
+func (p *Project) getAssetUrl(tag string, asset *github.RepositoryReleaseAsset) string {
+	for _, release := range p.proj.Releases {
+		if release.Tag != tag {
+			continue
+		}
+		if p.isDownloadsMirrored(release, asset) {
+			return path.Join(p.getDownloadsReleaseUrl(tag), asset.Name)
+		}
+		break
+	}
+	return asset.DownloadUrl
+}
+
+func (p *Project) getDownloadsAssetPath(release *github.RepositoryRelease, asset *github.RepositoryReleaseAsset) string {
+	d := &downloadsAsset{
+		proj:    p,
+		release: release,
+		asset:   asset,
+	}
+	return filepath.Join(p.BuildDirectory, p.GetBaseDestination(), d.GetDestination())
+}
+
+// getDownloadsAssetPaths returns the mirrored assets of the releases.
+func (p *Project) getDownloadsAssetPaths(releases ...*github.RepositoryRelease) []string {
+	rv := []string{}
+	for _, release := range releases {
+		for i := range release.Assets {
+			asset := &release.Assets[i]
+			if p.isDownloadsMirrored(release, asset) {
+				rv = append(rv, p.getDownloadsAssetPath(release, asset))
+			}
+		}
+	}
+	return rv
+}
+
+type downloadsAsset struct {
+	proj    *Project
+	release *github.RepositoryRelease
+	asset   *github.RepositoryReleaseAsset
+}
+
+func (d *downloadsAsset) GetDestination() string {
+	return filepath.Join(d.proj.slug(), d.proj.downloadsDestination, filepath.FromSlash(d.release.Tag), d.asset.Name)
+}
+
+func (d *downloadsAsset) GetGenerator() (runner.Generator, error) {
+	return &generators.HTTP{
+		Url:       d.asset.DownloadUrl,
+		Immutable: d.release.Tag != d.proj.RollingTag,
+	}, nil
+}
+
+type downloadsChecksums struct {
+	proj    *Project
+	release *github.RepositoryRelease
+	sha512  bool
+}
+
+func (d *downloadsChecksums) GetDestination() string {
+	name := "SHA256SUMS"
+	if d.sha512 {
+		name = "SHA512SUMS"
+	}
+	return filepath.Join(d.proj.slug(), d.proj.downloadsDestination, filepath.FromSlash(d.release.Tag), name)
+}
+
+func (d *downloadsChecksums) GetGenerator() (runner.Generator, error) {
+	return d, nil
+}
+
+func (*downloadsChecksums) GetID() string {
+	return "CHECKSUM"
+}
+
+func (d *downloadsChecksums) GetReader() (io.ReadCloser, error) {
+	buf := &bytes.Buffer{}
+	for i := range d.release.Assets {
+		asset := &d.release.Assets[i]
+		if !d.proj.isDownloadsMirrored(d.release, asset) {
+			continue
+		}
+
+		s256, s512, err := getDownloadsAssetChecksum(d.proj.getDownloadsAssetPath(d.release, asset))
+		if err != nil {
+			return nil, err
+		}
+		if d.sha512 {
+			buf.WriteString(downloadsChecksumsLine(s512, asset.Name))
+		} else {
+			buf.WriteString(downloadsChecksumsLine(s256, asset.Name))
+		}
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (d *downloadsChecksums) GetPaths() ([]string, error) {
+	return d.proj.getDownloadsAssetPaths(d.release), nil
+}
+
+func (d *downloadsChecksums) GetImmutable() bool {
+	return d.release.Tag != d.proj.RollingTag
+}
+
+func (*downloadsChecksums) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
+
+type downloads struct {
+	proj *Project
+	og   *opengraph.OpenGraph
+}
+
+func (d *downloads) GetDestination() string {
+	return filepath.Join(d.proj.slug(), d.proj.downloadsDestination, "index.html")
+}
+
+func (d *downloads) GetGenerator() (runner.Generator, error) {
+	return d, nil
+}
+
+func (*downloads) GetID() string {
+	return "DOWNLOADS"
+}
+
+func (d *downloads) getTemplate() string {
+	rv := d.proj.DownloadsTemplate
+	if rv == "" {
+		rv = "project-downloads.html"
+	}
+	return rv
+}
+
+func (d *downloads) GetReader() (io.ReadCloser, error) {
+	current := d.proj.downloadsDestination
+	absurl := path.Join(d.proj.url, current)
+
+	tmpl := d.proj.getTemplateEntry(current, func(pg *ProjectPage) bool {
+		return false
+	})
+	absoluteMenus(absurl, tmpl.Menus)
+
+	latest := ""
+	if d.proj.proj.LatestRelease != nil {
+		latest = d.proj.proj.LatestRelease.Tag
+	}
+
+	for _, release := range d.proj.getDownloadsReleases() {
+		name := release.Name
+		if name == "" {
+			name = release.Tag
+		}
+
+		rel := &templates.ProjectContentDownloadsRelease{
+			ID:         releaseId(release.Tag),
+			Name:       name,
+			Tag:        release.Tag,
+			URL:        release.Url,
+			Published:  release.Published,
+			Prerelease: release.Prerelease,
+			Latest:     release.Tag == latest,
+		}
+
+		files := map[string]*templates.ProjectContentDownloadsFile{}
+		for i := range release.Assets {
+			asset := &release.Assets[i]
+			f := &templates.ProjectContentDownloadsFile{
+				File: asset.Name,
+				URL:  d.proj.getAssetUrl(release.Tag, asset),
+				Size: asset.Size,
+			}
+			if d.proj.isDownloadsMirrored(release, asset) {
+				s256, s512, err := getDownloadsAssetChecksum(d.proj.getDownloadsAssetPath(release, asset))
+				if err != nil {
+					return nil, err
+				}
+				f.Mirrored = true
+				f.SHA256 = s256
+				f.SHA512 = s512
+				rel.SHA256SumsURL = path.Join(d.proj.getDownloadsReleaseUrl(release.Tag), "SHA256SUMS")
+				rel.SHA512SumsURL = path.Join(d.proj.getDownloadsReleaseUrl(release.Tag), "SHA512SUMS")
+			}
+			files[asset.Name] = f
+		}
+
+		for name, f := range files {
+			if signed, ok := downloadsSignatureOf(name); ok {
+				if sf, found := files[signed]; found {
+					sf.Signatures = append(sf.Signatures, f)
+					continue
+				}
+			}
+			rel.Files = append(rel.Files, f)
+		}
+		slices.SortFunc(rel.Files, func(a *templates.ProjectContentDownloadsFile, b *templates.ProjectContentDownloadsFile) int {
+			return strings.Compare(a.File, b.File)
+		})
+		for _, f := range rel.Files {
+			slices.SortFunc(f.Signatures, func(a *templates.ProjectContentDownloadsFile, b *templates.ProjectContentDownloadsFile) int {
+				return strings.Compare(a.File, b.File)
+			})
+		}
+		tmpl.Downloads = append(tmpl.Downloads, rel)
+	}
+
+	title := fmt.Sprintf("%s: Downloads", d.proj.slug())
+	description := fmt.Sprintf("Downloads of %s", d.proj.slug())
+	if d.proj.proj.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, d.proj.proj.Description)
+	}
+
+	og, err := opengraph.New(d.proj.OpenGraphImageGen, false, d.proj.downloadsUrl, title, description, d.proj.DownloadsOpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	d.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, d.getTemplate(), nil, nil, &templates.ContentContext{
+		Title:       title,
+		Description: description,
+		URL:         d.proj.downloadsUrl,
+		License:     d.proj.license,
+		Search:      true,
+		OpenGraph:   og.GetTemplateContext(),
+		Entry: &templates.ContentEntry{
+			Title:   title,
+			Project: tmpl,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (d *downloads) GetPaths() ([]string, error) {
+	// the mirrored assets are always listed, to wait for them to be generated
+	assets := d.proj.getDownloadsAssetPaths(d.proj.getDownloadsReleases()...)
+	if d.proj.immutable() {
+		return assets, nil
+	}
+
+	rv, err := templates.GetPaths(d.getTemplate())
+	if err != nil {
+		return nil, err
+	}
+	rv = append(rv, assets...)
+
+	if d.proj.OpenGraphImageGen != nil {
+		rv = append(rv, d.proj.OpenGraphImageGen.GetPaths()...)
+	}
+	rv = append(rv, d.proj.statePaths(false)...)
+	return rv, nil
+}
+
+func (d *downloads) GetImmutable() bool {
+	return d.proj.immutable()
+}
+
+func (d *downloads) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if d.og != nil {
+			d.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
+
+func (p *Project) initDownloads() error {
+	p.downloadsDestination = p.DownloadsDestination
+	if p.downloadsDestination == "" {
+		p.downloadsDestination = "downloads"
+	}
+
+	p.downloadsUrl = ""
+	p.downloadsMirrorPattern = nil
+	if !p.DownloadsEnabled {
+		return nil
+	}
+	p.downloadsUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.downloadsDestination) + "/"
+
+	if p.DownloadsMirrorPattern != "" {
+		re, err := regexp.Compile(p.DownloadsMirrorPattern)
+		if err != nil {
+			return fmt.Errorf("project: downloads: %w", err)
+		}
+		p.downloadsMirrorPattern = re
+	}
+	return nil
+}
+
+func (p *Project) getDownloadsTasks() ([]*runner.Task, error) {
+	rv := []*runner.Task{
+		runner.NewTask(p, &downloads{proj: p}),
+	}
+
+	for _, release := range p.getDownloadsReleases() {
+		if t := path.Clean(release.Tag); t != release.Tag || t == ".." || strings.HasPrefix(t, "../") || path.IsAbs(t) {
+			return nil, fmt.Errorf("project: downloads: invalid release tag: %s", release.Tag)
+		}
+
+		mirrored := false
+		for i := range release.Assets {
+			asset := &release.Assets[i]
+			if !p.isDownloadsMirrored(release, asset) {
+				continue
+			}
+			mirrored = true
+			rv = append(rv, runner.NewTask(p, &downloadsAsset{
+				proj:    p,
+				release: release,
+				asset:   asset,
+			}))
+		}
+
+		if mirrored {
+			rv = append(rv,
+				runner.NewTask(p, &downloadsChecksums{proj: p, release: release}),
+				runner.NewTask(p, &downloadsChecksums{proj: p, release: release, sha512: true}),
+			)
+		}
+	}
+	return rv, nil
+}
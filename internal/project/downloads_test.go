@@ -0,0 +1,159 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func TestDownloadsSignatureOf(t *testing.T) {
+	args := []struct {
+		name  string
+		exp   string
+		expOk bool
+	}{
+		{"foo.tar.gz.asc", "foo.tar.gz", true},
+		{"foo.tar.gz.sig", "foo.tar.gz", true},
+		{"foo.tar.gz.minisig", "foo.tar.gz", true},
+		{"foo.tar.gz", "", false},
+		{".asc", "", false},
+	}
+
+	for _, tt := range args {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := downloadsSignatureOf(tt.name)
+			if ok != tt.expOk {
+				t.Errorf("ok mismatch: got %v, want %v", ok, tt.expOk)
+			}
+			if got != tt.exp {
+				t.Errorf("got %q, want %q", got, tt.exp)
+			}
+		})
+	}
+}
+
+func newTestDownloadsProject() *Project {
+	return &Project{
+		RollingTag:             "rolling",
+		DownloadsReleases:      2,
+		downloadsUrl:           "/projects/foo/downloads/",
+		downloadsDestination:   "downloads",
+		downloadsMirrorPattern: regexp.MustCompile(`\.tar\.gz(\.asc)?$`),
+		proj: &github.Repository{
+			Releases: []*github.RepositoryRelease{
+				{
+					Tag: "v2",
+					Assets: []github.RepositoryReleaseAsset{
+						{Name: "foo-2.tar.gz", DownloadUrl: "https://example.org/foo-2.tar.gz"},
+						{Name: "foo-2.tar.gz.asc", DownloadUrl: "https://example.org/foo-2.tar.gz.asc"},
+						{Name: "foo-2.zip", DownloadUrl: "https://example.org/foo-2.zip"},
+					},
+				},
+				{
+					Tag: "v1",
+					Assets: []github.RepositoryReleaseAsset{
+						{Name: "foo-1.zip", DownloadUrl: "https://example.org/foo-1.zip"},
+					},
+				},
+				{
+					Tag: "v0",
+					Assets: []github.RepositoryReleaseAsset{
+						{Name: "foo-0.tar.gz", DownloadUrl: "https://example.org/foo-0.tar.gz"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDownloadsAssetUrl(t *testing.T) {
+	proj := newTestDownloadsProject()
+
+	args := []struct {
+		tag   string
+		asset int
+		exp   string
+	}{
+		{"v2", 0, "/projects/foo/downloads/v2/foo-2.tar.gz"},
+		{"v2", 1, "/projects/foo/downloads/v2/foo-2.tar.gz.asc"},
+		{"v2", 2, "https://example.org/foo-2.zip"},
+		{"v1", 0, "https://example.org/foo-1.zip"},
+		{"v0", 0, "https://example.org/foo-0.tar.gz"},
+	}
+
+	for _, tt := range args {
+		for _, release := range proj.proj.Releases {
+			if release.Tag != tt.tag {
+				continue
+			}
+			if got := proj.getAssetUrl(tt.tag, &release.Assets[tt.asset]); got != tt.exp {
+				t.Errorf("%s: got %q, want %q", release.Assets[tt.asset].Name, got, tt.exp)
+			}
+		}
+	}
+
+	proj.downloadsUrl = ""
+	if got := proj.getAssetUrl("v2", &proj.proj.Releases[0].Assets[0]); got != "https://example.org/foo-2.tar.gz" {
+		t.Errorf("disabled: got %q", got)
+	}
+}
+
+func TestDownloadsTasks(t *testing.T) {
+	proj := newTestDownloadsProject()
+
+	tasks, err := proj.getDownloadsTasks()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// index page, 2 mirrored assets and 2 checksum files for v2
+	if len(tasks) != 5 {
+		t.Errorf("got %d tasks, want 5", len(tasks))
+	}
+
+	proj.proj.Releases[0].Tag = "../v2"
+	if _, err := proj.getDownloadsTasks(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDownloadsAssetChecksum(t *testing.T) {
+	dir := t.TempDir()
+	proj := newTestDownloadsProject()
+	proj.Repo = "foo"
+	proj.BuildDirectory = dir
+
+	release := proj.proj.Releases[0]
+	fpath := proj.getDownloadsAssetPath(release, &release.Assets[0])
+	if exp := filepath.Join(dir, "projects", "foo", "downloads", "v2", "foo-2.tar.gz"); fpath != exp {
+		t.Errorf("bad asset path: got %q, want %q", fpath, exp)
+	}
+	if paths := proj.getDownloadsAssetPaths(proj.proj.Releases...); len(paths) != 2 || paths[0] != fpath {
+		t.Errorf("bad asset paths: %v", paths)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fpath), 0777); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.WriteFile(fpath, []byte("foo\n"), 0666); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s256, s512, err := getDownloadsAssetChecksum(fpath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if exp := "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"; s256 != exp {
+		t.Errorf("bad sha256: got %q, want %q", s256, exp)
+	}
+	if exp := "0cf9180a764aba863a67b6d72f0918bc131c6772642cb2dce5a34f0a702f9470ddc2bf125c12198b1995c233c34b4afd346c54a2334c350a948a51b6e8b4e6b6"; s512 != exp {
+		t.Errorf("bad sha512: got %q, want %q", s512, exp)
+	}
+
+	if _, _, err := getDownloadsAssetChecksum(filepath.Join(dir, "bola")); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -1,6 +1,7 @@
 package project
 
 import (
+	"path"
 	"path/filepath"
 
 	"rafaelmartins.com/p/website/internal/generators"
@@ -13,19 +14,21 @@ type fileTask struct {
 }
 
 func (i *fileTask) GetDestination() string {
-	return filepath.Join(i.proj.Repo, filepath.FromSlash(string(i.path)))
+	return filepath.Join(i.proj.slug(), filepath.FromSlash(string(i.path)))
 }
 
 func (i *fileTask) GetGenerator() (runner.Generator, error) {
 	if i.proj.LocalDirectory != nil {
-		return generators.File(filepath.Join(*i.proj.LocalDirectory, i.path)), nil
+		return generators.File(i.proj.localPath(i.path)), nil
 	}
 
 	return &generators.GithubFile{
 		Owner:     i.proj.Owner,
 		Repo:      i.proj.Repo,
 		Ref:       i.proj.proj.Head,
-		Path:      i.path,
-		Immutable: i.proj.Immutable,
+		Path:      path.Join(i.proj.subdirectory(), i.path),
+		Immutable: i.proj.Immutable && i.proj.TTL <= 0,
+
+		ExtraDependencies: i.proj.statePaths(false),
 	}, nil
 }
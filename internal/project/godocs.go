@@ -0,0 +1,226 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/godocs"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type goDocs struct {
+	proj *Project
+	dir  string
+	pkg  *godocs.Package
+	og   *opengraph.OpenGraph
+}
+
+func (g *goDocs) url() string {
+	if g.dir == "." {
+		return g.proj.godocsUrl
+	}
+	return path.Join(g.proj.godocsUrl, g.dir) + "/"
+}
+
+func (g *goDocs) GetDestination() string {
+	return filepath.Join(g.proj.slug(), g.proj.godocsDestination, filepath.FromSlash(g.dir), "index.html")
+}
+
+func (g *goDocs) GetGenerator() (runner.Generator, error) {
+	return g, nil
+}
+
+func (*goDocs) GetID() string {
+	return "GODOCS"
+}
+
+func (g *goDocs) getTemplate() string {
+	rv := g.proj.GoDocsTemplate
+	if rv == "" {
+		rv = "godocs.html"
+	}
+	return rv
+}
+
+func (g *goDocs) GetReader() (io.ReadCloser, error) {
+	// subpackages are nested deeper than the regular project pages, use
+	// absolute urls everywhere to avoid dealing with relative paths.
+	current := g.proj.godocsDestination
+	absurl := path.Join(g.proj.url, current)
+
+	tmpl := g.proj.getTemplateEntry(current, func(pg *ProjectPage) bool {
+		return false
+	})
+	absoluteMenus(absurl, tmpl.Menus)
+
+	packages := []*godocs.PackageCtx{}
+	for _, pkg := range g.proj.godocsPackages {
+		if pkg.Dir == g.dir || (g.dir != "." && !strings.HasPrefix(pkg.Dir, g.dir+"/")) {
+			continue
+		}
+
+		u := g.proj.godocsUrl
+		if pkg.Dir != "." {
+			u = path.Join(u, pkg.Dir) + "/"
+		}
+		packages = append(packages, &godocs.PackageCtx{
+			ImportPath: pkg.ImportPath,
+			Name:       pkg.Doc.Name,
+			Synopsis:   pkg.Doc.Synopsis(pkg.Doc.Doc),
+			URL:        u,
+		})
+	}
+
+	dctx := &godocs.TemplateCtx{
+		ImportPath: g.proj.GoImport,
+		Packages:   packages,
+	}
+	if g.pkg != nil {
+		baseUrl := g.proj.blobUrl(g.proj.proj.Head)
+
+		var err error
+		dctx, err = godocs.NewTemplateCtx(g.pkg, baseUrl, packages)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	title := fmt.Sprintf("%s: Go API Documentation", g.proj.slug())
+	if g.pkg != nil && g.dir != "." {
+		title = fmt.Sprintf("%s: Go API Documentation: %s", g.proj.slug(), g.pkg.ImportPath)
+	}
+
+	description := fmt.Sprintf("Go API documentation of %s", dctx.ImportPath)
+	if dctx.Synopsis != "" {
+		description = fmt.Sprintf("%s: %s", description, dctx.Synopsis)
+	}
+
+	// all the packages share the opengraph image of the index page
+	og, err := opengraph.New(g.proj.OpenGraphImageGen, g.dir != ".", g.proj.godocsUrl, title, description, g.proj.GoDocsOpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	g.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, g.getTemplate(), nil, nil, &templates.ContentContext{
+		Title:       title,
+		Description: description,
+		URL:         g.url(),
+		License:     g.proj.license,
+		Search:      true,
+		OpenGraph:   og.GetTemplateContext(),
+		Entry: &templates.ContentEntry{
+			Title:   title,
+			Project: tmpl,
+			GoDocs:  dctx,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (g *goDocs) GetPaths() ([]string, error) {
+	if g.proj.immutable() {
+		return nil, nil
+	}
+
+	rv, err := templates.GetPaths(g.getTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	if g.proj.LocalDirectory != nil {
+		for _, f := range g.proj.godocsFiles {
+			rv = append(rv, g.proj.localPath(f))
+		}
+	}
+
+	if g.proj.OpenGraphImageGen != nil {
+		rv = append(rv, g.proj.OpenGraphImageGen.GetPaths()...)
+	}
+	rv = append(rv, g.proj.statePaths(false)...)
+	return rv, nil
+}
+
+func (g *goDocs) GetImmutable() bool {
+	return g.proj.immutable()
+}
+
+func (g *goDocs) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if g.og != nil {
+			g.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
+
+func (p *Project) initGoDocs() error {
+	// remote repositories are pinned to a commit, no need to fetch the sources again
+	if p.godocsPackages != nil && p.LocalDirectory == nil {
+		return nil
+	}
+
+	rfiles, err := p.proj.ListFiles(godocs.IsSupported)
+	if err != nil {
+		return err
+	}
+
+	files := []*godocs.File{}
+	names := []string{}
+	for _, rf := range rfiles {
+		data, err := rf.Read()
+		if err != nil {
+			return err
+		}
+		files = append(files, &godocs.File{
+			Name: rf.Name,
+			Data: data,
+		})
+		names = append(names, rf.Name)
+	}
+
+	pkgs, err := godocs.Parse(p.GoImport, files, p.GoDocsInternal, p.GoDocsGOOS, p.GoDocsGOARCH)
+	if err != nil {
+		return err
+	}
+	p.godocsPackages = pkgs
+	p.godocsFiles = names
+	return nil
+}
+
+func (p *Project) getGoDocsTasks() ([]*runner.Task, error) {
+	if err := p.initGoDocs(); err != nil {
+		return nil, err
+	}
+
+	rv := []*runner.Task{}
+	root := false
+	for _, pkg := range p.godocsPackages {
+		if pkg.Dir == "." {
+			root = true
+		}
+		rv = append(rv, runner.NewTask(p, &goDocs{
+			proj: p,
+			dir:  pkg.Dir,
+			pkg:  pkg,
+		}))
+	}
+
+	// the index page is always generated, listing the packages if the root is not a package
+	if !root {
+		rv = append(rv, runner.NewTask(p, &goDocs{
+			proj: p,
+			dir:  ".",
+		}))
+	}
+	return rv, nil
+}
@@ -0,0 +1,94 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type goImport struct {
+	proj *Project
+	pkg  string
+}
+
+func (g *goImport) GetDestination() string {
+	return filepath.Join(g.proj.slug(), filepath.FromSlash(g.pkg), "index.html")
+}
+
+func (g *goImport) GetGenerator() (runner.Generator, error) {
+	return g, nil
+}
+
+func (*goImport) GetID() string {
+	return "GO-IMPORT"
+}
+
+func (g *goImport) GetReader() (io.ReadCloser, error) {
+	repo := g.proj.GoRepo
+	if repo == "" {
+		repo = g.proj.Repo
+	}
+	repoUrl := "https://github.com/" + g.proj.Owner + "/" + repo
+
+	// the module lives in the project subdirectory, unless it comes from another repository
+	srcDir := ""
+	if g.proj.GoRepo == "" && g.proj.subdirectory() != "" {
+		srcDir = "/" + g.proj.subdirectory()
+	}
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, "go-import.html", nil, nil, &templates.ContentContext{
+		Title: path.Join(g.proj.GoImport, g.pkg),
+		URL:   path.Join(g.proj.url, g.pkg) + "/",
+		Entry: &templates.ContentEntry{
+			GoImport: &templates.GoImportContentEntry{
+				Prefix:        g.proj.GoImport,
+				VCS:           "git",
+				RepoURL:       repoUrl + ".git",
+				SourceURL:     repoUrl,
+				SourceDirURL:  repoUrl + "/tree/HEAD" + srcDir + "{/dir}",
+				SourceFileURL: repoUrl + "/blob/HEAD" + srcDir + "{/dir}/{file}#L{line}",
+				RedirectURL:   g.proj.url,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (g *goImport) GetPaths() ([]string, error) {
+	return templates.GetPaths("go-import.html")
+}
+
+func (*goImport) GetImmutable() bool {
+	return false
+}
+
+func (*goImport) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		close(ch)
+	}
+}
+
+func (p *Project) getGoImportTasks() ([]*runner.Task, error) {
+	rv := []*runner.Task{}
+	for _, pkg := range p.GoPackages {
+		v := path.Clean(strings.Trim(pkg, "/"))
+		if v == "." || v == ".." || strings.HasPrefix(v, "../") {
+			return nil, fmt.Errorf("project: invalid go package path: %s", pkg)
+		}
+
+		rv = append(rv, runner.NewTask(p, &goImport{
+			proj: p,
+			pkg:  v,
+		}))
+	}
+	return rv, nil
+}
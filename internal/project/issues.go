@@ -0,0 +1,184 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark/parser"
+	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/markdown"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+func issueLabelId(label string) string {
+	rv := reReleaseIdFilter.ReplaceAllString(label, " ")
+	rv = strings.TrimSpace(rv)
+	rv = strings.ReplaceAll(rv, " ", "-")
+	return "label-" + strings.ToLower(rv)
+}
+
+func milestoneProgress(open int, closed int) int {
+	if open+closed == 0 {
+		return 0
+	}
+	return closed * 100 / (open + closed)
+}
+
+type issues struct {
+	proj *Project
+	og   *opengraph.OpenGraph
+}
+
+func (i *issues) GetDestination() string {
+	return filepath.Join(i.proj.slug(), i.proj.issuesDestination, "index.html")
+}
+
+func (i *issues) GetGenerator() (runner.Generator, error) {
+	return i, nil
+}
+
+func (*issues) GetID() string {
+	return "ISSUES"
+}
+
+func (i *issues) getTemplate() string {
+	rv := i.proj.IssuesTemplate
+	if rv == "" {
+		rv = "project-issues.html"
+	}
+	return rv
+}
+
+func (i *issues) GetReader() (io.ReadCloser, error) {
+	iss, err := github.GetRepositoryIssues(i.proj.Owner, i.proj.Repo, i.proj.IssuesLabels, i.proj.IssuesMilestones)
+	if err != nil {
+		return nil, err
+	}
+
+	current := i.proj.issuesDestination
+	absurl := path.Join(i.proj.url, current)
+
+	tmpl := i.proj.getTemplateEntry(current, func(pg *ProjectPage) bool {
+		return false
+	})
+	absoluteMenus(absurl, tmpl.Menus)
+
+	for _, milestone := range iss.Milestones {
+		body := ""
+		if milestone.Description != "" {
+			pc := parser.NewContext()
+			pc.Set(pcProjectKey, i.proj)
+			pc.Set(pcBaseUrlKey, i.proj.blobUrl(i.proj.proj.Head))
+			pc.Set(markdown.PcReferences, &markdown.References{Owner: i.proj.Owner, Repo: i.proj.Repo})
+			pc.Set(pcCurrentPageKey, current)
+			pc.Set(pcAbsoluteUrlKey, absurl)
+			i.proj.setSanitize(pc, "milestone "+milestone.Title)
+
+			_, body, err = markdown.Render(gmMarkdown, []byte(milestone.Description), pc)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tmpl.Milestones = append(tmpl.Milestones, &templates.ProjectContentMilestone{
+			Title:        milestone.Title,
+			Body:         template.HTML(body),
+			URL:          milestone.Url,
+			DueOn:        milestone.DueOn,
+			OpenIssues:   milestone.OpenIssues,
+			ClosedIssues: milestone.ClosedIssues,
+			Progress:     milestoneProgress(milestone.OpenIssues, milestone.ClosedIssues),
+		})
+	}
+
+	for _, label := range i.proj.IssuesLabels {
+		group := &templates.ProjectContentIssueGroup{
+			ID:    issueLabelId(label),
+			Label: label,
+		}
+		for _, issue := range iss.ByLabel(label) {
+			is := &templates.ProjectContentIssue{
+				Number:    issue.Number,
+				Title:     issue.Title,
+				URL:       issue.Url,
+				Created:   issue.Created,
+				Author:    issue.Author,
+				Milestone: issue.Milestone,
+			}
+			for _, l := range issue.Labels {
+				is.Labels = append(is.Labels, &templates.ProjectContentIssueLabel{
+					Name:  l.Name,
+					Color: l.Color,
+				})
+			}
+			group.Issues = append(group.Issues, is)
+		}
+		tmpl.IssueGroups = append(tmpl.IssueGroups, group)
+	}
+
+	title := fmt.Sprintf("%s: Issues", i.proj.slug())
+	description := fmt.Sprintf("Milestones and open issues of %s", i.proj.slug())
+	if i.proj.proj.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, i.proj.proj.Description)
+	}
+
+	og, err := opengraph.New(i.proj.OpenGraphImageGen, false, i.proj.issuesUrl, title, description, i.proj.IssuesOpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	i.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, i.getTemplate(), nil, nil, &templates.ContentContext{
+		Title:       title,
+		Description: description,
+		URL:         i.proj.issuesUrl,
+		License:     i.proj.license,
+		Search:      true,
+		OpenGraph:   og.GetTemplateContext(),
+		Entry: &templates.ContentEntry{
+			Title:   title,
+			Project: tmpl,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (i *issues) GetPaths() ([]string, error) {
+	if i.proj.immutable() {
+		return nil, nil
+	}
+
+	rv, err := templates.GetPaths(i.getTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	if i.proj.OpenGraphImageGen != nil {
+		rv = append(rv, i.proj.OpenGraphImageGen.GetPaths()...)
+	}
+	rv = append(rv, i.proj.statePaths(false)...)
+	return rv, nil
+}
+
+func (i *issues) GetImmutable() bool {
+	return i.proj.immutable()
+}
+
+func (i *issues) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if i.og != nil {
+			i.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
@@ -0,0 +1,45 @@
+package project
+
+import (
+	"testing"
+)
+
+func TestIssueLabelId(t *testing.T) {
+	args := []struct {
+		label string
+		exp   string
+	}{
+		{"roadmap", "label-roadmap"},
+		{"good first issue", "label-good-first-issue"},
+		{"Help Wanted!", "label-help-wanted"},
+		{"type: bug", "label-type-bug"},
+	}
+
+	for _, tt := range args {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := issueLabelId(tt.label); got != tt.exp {
+				t.Errorf("got %q, want %q", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestMilestoneProgress(t *testing.T) {
+	args := []struct {
+		open   int
+		closed int
+		exp    int
+	}{
+		{0, 0, 0},
+		{1, 0, 0},
+		{0, 3, 100},
+		{1, 1, 50},
+		{2, 1, 33},
+	}
+
+	for _, tt := range args {
+		if got := milestoneProgress(tt.open, tt.closed); got != tt.exp {
+			t.Errorf("milestoneProgress(%d, %d): got %d, want %d", tt.open, tt.closed, got, tt.exp)
+		}
+	}
+}
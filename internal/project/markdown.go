@@ -1,18 +1,24 @@
 package project
 
 import (
+	"path"
+	"strings"
+
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 	"rafaelmartins.com/p/website/internal/markdown"
+	"rafaelmartins.com/p/website/internal/templates"
 )
 
 var (
 	pcProjectKey     = parser.NewContextKey()
 	pcBaseUrlKey     = parser.NewContextKey()
 	pcCurrentPageKey = parser.NewContextKey()
+	pcSourceDirKey   = parser.NewContextKey()
+	pcAbsoluteUrlKey = parser.NewContextKey()
 	pcTitleKey       = parser.NewContextKey()
 	pcImagesKey      = parser.NewContextKey()
 	pcErrorKey       = parser.NewContextKey()
@@ -20,6 +26,30 @@ var (
 	gmMarkdown = markdown.New("github", &extension{})
 )
 
+func absoluteUrl(base string, u string) string {
+	rv := path.Join(base, u)
+	if strings.HasSuffix(u, "/") && rv != "/" {
+		rv += "/"
+	}
+	return rv
+}
+
+func absoluteMenus(base string, menus []*templates.ProjectContentMenu) {
+	for _, menu := range menus {
+		if menu.URL != "" {
+			menu.URL = absoluteUrl(base, menu.URL)
+		}
+		absoluteMenus(base, menu.Children)
+	}
+}
+
+// content from the repositories is sanitized, unless explicitly disabled.
+func (p *Project) setSanitize(pc parser.Context, name string) {
+	if !p.UnsafeHTML {
+		pc.Set(markdown.PcSanitize, p.Owner+"/"+p.Repo+": "+name)
+	}
+}
+
 type extension struct{}
 
 func (e *extension) Extend(m goldmark.Markdown) {
@@ -38,6 +68,14 @@ func (extension) Transform(node *ast.Document, reader text.Reader, pc parser.Con
 
 	proj := pc.Get(pcProjectKey).(*Project)
 	baseurl := pc.Get(pcBaseUrlKey).(string)
+	absurl, _ := pc.Get(pcAbsoluteUrlKey).(string)
+
+	// relative urls are resolved from the directory of the source file, or
+	// from the documentation directory for content that is not a page.
+	srcDir, ok := pc.Get(pcSourceDirKey).(string)
+	if !ok {
+		srcDir = proj.subdir
+	}
 	images := []string{}
 
 	pc.Set(pcErrorKey, ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
@@ -48,7 +86,7 @@ func (extension) Transform(node *ast.Document, reader text.Reader, pc parser.Con
 		switch n.Kind() {
 		case ast.KindImage:
 			img := n.(*ast.Image)
-			p, u, err := proj.handleImageUrl(string(img.Destination), pc.Get(pcCurrentPageKey).(string))
+			p, u, err := proj.handleImageUrl(string(img.Destination), srcDir, pc.Get(pcCurrentPageKey).(string))
 			if err != nil {
 				return 0, err
 			}
@@ -57,12 +95,15 @@ func (extension) Transform(node *ast.Document, reader text.Reader, pc parser.Con
 				images = append(images, p)
 			}
 			if u != "" {
+				if absurl != "" && p != "" {
+					u = absoluteUrl(absurl, u)
+				}
 				img.Destination = []byte(u)
 			}
 
 		case ast.KindLink:
 			link := n.(*ast.Link)
-			gh, u, err := proj.handleLinkUrl(string(link.Destination), pc.Get(pcCurrentPageKey).(string))
+			gh, u, err := proj.handleLinkUrl(string(link.Destination), srcDir, pc.Get(pcCurrentPageKey).(string))
 			if err != nil {
 				return 0, err
 			}
@@ -71,6 +112,9 @@ func (extension) Transform(node *ast.Document, reader text.Reader, pc parser.Con
 				if gh {
 					link.Destination = []byte(baseurl + "/" + u)
 				} else {
+					if absurl != "" && !strings.HasPrefix(string(link.Destination), "@@") {
+						u = absoluteUrl(absurl, u)
+					}
 					link.Destination = []byte(u)
 				}
 			}
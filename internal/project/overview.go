@@ -0,0 +1,98 @@
+package project
+
+import (
+	"path"
+	"time"
+
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+const overviewFile = "overview.json"
+
+// projectOverview is the remote data of the project used by the projects
+// overview page. it is cached in the state directory, to be reused when the
+// project is not built.
+type projectOverview struct {
+	Description       string     `json:"description,omitempty"`
+	License           string     `json:"license,omitempty"`
+	Stars             int        `json:"stars"`
+	LatestRelease     string     `json:"latest-release,omitempty"`
+	LatestReleaseDate *time.Time `json:"latest-release-date,omitempty"`
+}
+
+func (p *Project) overview() *projectOverview {
+	rv := &projectOverview{
+		Description: p.proj.Description,
+		License:     p.proj.LicenseSpdx,
+		Stars:       p.proj.Stars,
+	}
+	if p.proj.LatestRelease != nil {
+		published := p.proj.LatestRelease.Published
+		rv.LatestRelease = p.proj.LatestRelease.Tag
+		rv.LatestReleaseDate = &published
+	}
+	return rv
+}
+
+func (p *Project) updateOverview() error {
+	return p.writeState(overviewFile, p.overview())
+}
+
+// GetOverviewPaths returns the files the overview of the project depends on.
+func (p *Project) GetOverviewPaths() []string {
+	return []string{p.statePath(overviewFile)}
+}
+
+// GetOverview returns the summary of the project used by the projects
+// overview page. projects that were not built are read from the state
+// directory, and only initialized if not found there.
+func (p *Project) GetOverview() (*templates.ProjectsOverviewContentProject, error) {
+	var ov *projectOverview
+	if p.proj != nil {
+		ov = p.overview()
+	} else {
+		cached := &projectOverview{}
+		found, err := p.readState(overviewFile, cached)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			ov = cached
+		} else {
+			if err := p.init(); err != nil {
+				return nil, err
+			}
+			if err := p.updateOverview(); err != nil {
+				return nil, err
+			}
+			ov = p.overview()
+		}
+	}
+
+	url := path.Join("/", p.GetBaseDestination(), p.slug())
+	if url != "/" {
+		url += "/"
+	}
+
+	// licenses from the configuration win
+	license := ov.License
+	if len(p.Licenses) > 0 {
+		license = p.Licenses[0].SpdxId
+	}
+
+	return &templates.ProjectsOverviewContentProject{
+		Owner:             p.Owner,
+		Repo:              p.Repo,
+		Name:              p.slug(),
+		URL:               url,
+		Description:       ov.Description,
+		License:           license,
+		Stars:             ov.Stars,
+		LatestRelease:     ov.LatestRelease,
+		LatestReleaseDate: ov.LatestReleaseDate,
+		CDocs:             len(p.CDocsHeaders) > 0,
+		GoDocs:            p.GoDocsEnabled && p.GoImport != "",
+		Dfu:               p.DfuReleaseAssetsPattern != "",
+		Kicad:             p.KicadEnabled,
+	}, nil
+}
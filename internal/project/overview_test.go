@@ -0,0 +1,102 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func TestGetOverview(t *testing.T) {
+	published := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	proj := &Project{
+		Owner:        "bar",
+		Repo:         "foo",
+		KicadEnabled: true,
+		CDocsHeaders: []string{"foo.h"},
+		Licenses:     []*ProjectLicense{{SpdxId: "BSD-3-Clause"}},
+		proj: &github.Repository{
+			Description: "a project",
+			Stars:       42,
+			LatestRelease: &github.RepositoryLatestRelease{
+				Tag:       "v1.2.3",
+				Published: published,
+			},
+		},
+	}
+
+	ov, err := proj.GetOverview()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if ov.Owner != "bar" || ov.Repo != "foo" || ov.Name != "foo" || ov.URL != "/projects/foo/" {
+		t.Errorf("bad identification: %+v", ov)
+	}
+	if ov.Description != "a project" || ov.License != "BSD-3-Clause" || ov.Stars != 42 {
+		t.Errorf("bad metadata: %+v", ov)
+	}
+	if ov.LatestRelease != "v1.2.3" || ov.LatestReleaseDate == nil || !ov.LatestReleaseDate.Equal(published) {
+		t.Errorf("bad latest release: %+v", ov)
+	}
+	if !ov.CDocs || ov.GoDocs || ov.Dfu || !ov.Kicad {
+		t.Errorf("bad artifacts: %+v", ov)
+	}
+}
+
+func TestGetOverviewWithoutRelease(t *testing.T) {
+	proj := &Project{
+		Repo: "foo",
+		proj: &github.Repository{},
+	}
+
+	ov, err := proj.GetOverview()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ov.LatestRelease != "" || ov.LatestReleaseDate != nil {
+		t.Errorf("unexpected latest release: %+v", ov)
+	}
+}
+
+func TestGetOverviewFromState(t *testing.T) {
+	dir := t.TempDir()
+	proj := &Project{
+		Owner:          "bar",
+		Repo:           "foo",
+		StateDirectory: dir,
+		proj: &github.Repository{
+			Description: "a project",
+			LicenseSpdx: "MIT",
+			Stars:       42,
+		},
+	}
+	if err := proj.updateOverview(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if p := proj.GetOverviewPaths(); len(p) != 1 || p[0] != filepath.Join(dir, "projects", "foo", overviewFile) {
+		t.Errorf("unexpected overview paths: %v", p)
+	}
+	if _, err := os.Stat(proj.GetOverviewPaths()[0]); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// projects that were not built must not be fetched
+	proj.proj = nil
+	ov, err := proj.GetOverview()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if proj.proj != nil {
+		t.Errorf("project initialized")
+	}
+	if ov.Description != "a project" || ov.License != "MIT" || ov.Stars != 42 || ov.URL != "/projects/foo/" {
+		t.Errorf("bad metadata: %+v", ov)
+	}
+	if ov.LatestRelease != "" || ov.LatestReleaseDate != nil {
+		t.Errorf("unexpected latest release: %+v", ov)
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"path"
 	"path/filepath"
 	"slices"
-	"strconv"
 	"strings"
 
 	"github.com/yuin/goldmark/parser"
@@ -20,31 +20,25 @@ import (
 	"rafaelmartins.com/p/website/internal/templates"
 )
 
+// the numeric prefix of file names is optional, and only used for sorting
+// when the pages do not define a weight.
 func splitFileName(fileName string, isReadme bool, isRoot bool) (int, string, error) {
 	if isReadme {
 		return 0, "", nil
 	}
 
-	s := strings.SplitN(path.Base(fileName), "_", 2)
-	if len(s) != 2 {
-		return 0, "", fmt.Errorf("project: page: bad file name: %s", fileName)
-	}
-
-	i, err := strconv.ParseInt(s[0], 10, 32)
-	if err != nil {
-		return 0, "", err
-	}
-	idx := int(i)
-
-	if idx == 0 && !isRoot {
+	idx, name, found := splitPrefix(path.Base(fileName))
+	if found && idx == 0 && !isRoot {
 		return 0, "", fmt.Errorf("project: page: root page must be index 0: %s", fileName)
 	}
+	if name == "" {
+		return 0, "", fmt.Errorf("project: page: bad file name: %s", fileName)
+	}
 
-	name := ""
-	if !isRoot {
-		name = strings.TrimSuffix(s[1], path.Ext(s[1]))
+	if isRoot {
+		return idx, "", nil
 	}
-	return idx, name, nil
+	return idx, strings.TrimSuffix(name, path.Ext(name)), nil
 }
 
 type projectPageResolver struct {
@@ -52,18 +46,14 @@ type projectPageResolver struct {
 	name string
 }
 
-func newPageResolver(file *github.RepositoryFile, isReadme bool, isRoot bool) (*projectPageResolver, error) {
-	if file == nil {
+func newPageResolver(doc *projectDoc) (*projectPageResolver, error) {
+	if doc == nil || doc.file == nil {
 		return nil, errors.New("project: page resolver: file is nil")
 	}
 
-	_, name, err := splitFileName(file.Name, isReadme, isRoot)
-	if err != nil {
-		return nil, err
-	}
 	return &projectPageResolver{
-		src:  file.Name,
-		name: name,
+		src:  doc.file.Name,
+		name: doc.name,
 	}, nil
 }
 
@@ -75,13 +65,12 @@ func (ppr *projectPageResolver) resolveUrl(current string) string {
 
 	prefix := ""
 	if current != "." {
-		prefix = ".."
+		prefix = strings.Repeat("../", strings.Count(current, "/")+1)
 	}
 	return path.Join(prefix, ppr.name) + "/"
 }
 
 type ProjectPage struct {
-	idx    int
 	name   string
 	title  string
 	etitle string
@@ -89,6 +78,7 @@ type ProjectPage struct {
 	body   string
 	menu   string
 	src    string
+	srcDir string
 	isRoot bool
 
 	proj     *Project
@@ -100,29 +90,29 @@ type ProjectPage struct {
 	images []string
 }
 
-func newPage(proj *Project, file *github.RepositoryFile, isReadme bool, isRoot bool) (*ProjectPage, error) {
-	if file == nil {
+func newPage(proj *Project, doc *projectDoc) (*ProjectPage, error) {
+	if doc == nil || doc.file == nil {
 		return nil, errors.New("project: page: file is nil")
 	}
 
-	idx, name, err := splitFileName(file.Name, isReadme, isRoot)
+	resolver, err := newPageResolver(doc)
 	if err != nil {
 		return nil, err
 	}
 
-	resolver, err := newPageResolver(file, isReadme, isRoot)
-	if err != nil {
-		return nil, err
+	srcDir := path.Dir(doc.file.Name)
+	if srcDir == "." {
+		srcDir = ""
 	}
 
 	rv := &ProjectPage{
-		idx:    idx,
-		name:   name,
-		src:    file.Name,
-		isRoot: isRoot,
+		name:   doc.name,
+		src:    doc.file.Name,
+		srcDir: srcDir,
+		isRoot: doc.isRoot,
 
 		proj:     proj,
-		file:     file,
+		file:     doc.file,
 		resolver: resolver,
 	}
 
@@ -150,9 +140,12 @@ func (pp *ProjectPage) read() error {
 
 	pc := parser.NewContext()
 	pc.Set(pcProjectKey, pp.proj)
-	pc.Set(pcBaseUrlKey, "https://github.com/"+pp.proj.Owner+"/"+pp.proj.Repo+"/blob/"+pp.proj.proj.Head)
+	pc.Set(pcBaseUrlKey, pp.proj.blobUrl(pp.proj.proj.Head))
+	pc.Set(markdown.PcReferences, &markdown.References{Owner: pp.proj.Owner, Repo: pp.proj.Repo})
 	pc.Set(pcCurrentPageKey, pp.name)
+	pc.Set(pcSourceDirKey, pp.srcDir)
 	pc.Set(markdown.PcTocEnable, &withToc)
+	pp.proj.setSanitize(pc, pp.file.Name)
 
 	toc, body, err := markdown.Render(gmMarkdown, data, pc)
 	if err != nil {
@@ -185,19 +178,19 @@ func (pp *ProjectPage) read() error {
 
 	pp.title = etitle
 	if pp.title != "" && !pp.isRoot {
-		pp.title = pp.proj.Repo + ": " + pp.title
+		pp.title = pp.proj.slug() + ": " + pp.title
 	}
 	if pp.title == "" {
-		pp.title = pp.proj.Repo
+		pp.title = pp.proj.slug()
 	}
 	return nil
 }
 
 func (pp *ProjectPage) GetDestination() string {
 	if pp.isRoot {
-		return filepath.Join(pp.proj.Repo, "index.html")
+		return filepath.Join(pp.proj.slug(), "index.html")
 	}
-	return filepath.Join(pp.proj.Repo, pp.name, "index.html")
+	return filepath.Join(pp.proj.slug(), filepath.FromSlash(pp.name), "index.html")
 }
 
 func (pp *ProjectPage) GetGenerator() (runner.Generator, error) {
@@ -217,40 +210,24 @@ func (pp *ProjectPage) getTemplate() string {
 }
 
 func (pp *ProjectPage) GetReader() (io.ReadCloser, error) {
-	tmpl := &templates.ProjectContentEntry{
-		Owner:       pp.proj.Owner,
-		Repo:        pp.proj.Repo,
-		URL:         pp.proj.proj.HomepageUrl,
-		Description: pp.proj.proj.Description,
-		GoImport:    pp.proj.GoImport,
-		GoRepo:      pp.proj.GoRepo,
-		CDocsURL:    pp.proj.cdocsUrl,
-		Stars:       pp.proj.proj.Stars,
-		Watching:    pp.proj.proj.Watchers,
-		Forks:       pp.proj.proj.Forks,
-		IsRoot:      pp.isRoot,
-	}
-
 	if pp.proj.LocalDirectory != nil {
 		if err := pp.read(); err != nil {
 			return nil, err
 		}
 	}
 
-	if len(pp.proj.Licenses) > 0 {
-		for _, lic := range pp.proj.Licenses {
-			tmpl.Licenses = append(tmpl.Licenses, &templates.ProjectContentLicense{
-				SpdxId: lic.SpdxId,
-				Title:  lic.Title,
-			})
-		}
-	}
+	tmpl := pp.proj.getTemplateEntry(pp.name, func(pg *ProjectPage) bool {
+		return pg == pp
+	})
+	tmpl.IsRoot = pp.isRoot
 
 	if pp.isRoot && pp.proj.proj.LatestRelease != nil && pp.proj.proj.LatestRelease.Description != "" {
 		pc := parser.NewContext()
 		pc.Set(pcProjectKey, pp.proj)
-		pc.Set(pcBaseUrlKey, "https://github.com/"+pp.proj.Owner+"/"+pp.proj.Repo+"/blob/"+pp.proj.proj.LatestRelease.Tag)
+		pc.Set(pcBaseUrlKey, pp.proj.blobUrl(pp.proj.proj.LatestRelease.Tag))
+		pc.Set(markdown.PcReferences, &markdown.References{Owner: pp.proj.Owner, Repo: pp.proj.Repo})
 		pc.Set(pcCurrentPageKey, pp.name)
+		pp.proj.setSanitize(pc, "release "+pp.proj.proj.LatestRelease.Tag)
 
 		_, body, err := markdown.Render(gmMarkdown, []byte(pp.proj.proj.LatestRelease.Description), pc)
 		if err != nil {
@@ -260,14 +237,16 @@ func (pp *ProjectPage) GetReader() (io.ReadCloser, error) {
 		tmpl.LatestRelease = &templates.ProjectContentLatestRelease{
 			Name: pp.proj.proj.LatestRelease.Name,
 			Tag:  pp.proj.proj.LatestRelease.Tag,
-			Body: body,
+			Body: template.HTML(body),
 			URL:  pp.proj.proj.LatestRelease.Url,
 		}
 		for _, asset := range pp.proj.proj.LatestRelease.Assets {
 			tmpl.LatestRelease.Files = append(tmpl.LatestRelease.Files,
 				&templates.ProjectContentLatestReleaseFile{
-					File: asset.Name,
-					URL:  asset.DownloadUrl,
+					File:          asset.Name,
+					URL:           pp.proj.getAssetUrl(pp.proj.proj.LatestRelease.Tag, &asset),
+					Size:          asset.Size,
+					DownloadCount: asset.DownloadCount,
 				},
 			)
 		}
@@ -285,20 +264,6 @@ func (pp *ProjectPage) GetReader() (io.ReadCloser, error) {
 		WithSidebar: pp.isRoot,
 	}
 
-	tmpl.Menus = nil
-	for _, p := range pp.proj.pages {
-		// FIXME: max 9 sub pages???
-		if p.idx%10 != 0 {
-			continue
-		}
-
-		tmpl.Menus = append(tmpl.Menus, &templates.ProjectContentMenu{
-			Active: p.idx/10 == pp.idx/10,
-			URL:    p.resolver.resolveUrl(pp.name),
-			Title:  p.menu,
-		})
-	}
-
 	og, err := opengraph.New(pp.proj.OpenGraphImageGen, false, purl, pp.title, pp.proj.proj.Description, pp.proj.OpenGraph, pp.meta.Title, pp.meta.Description, pp.meta.OpenGraph)
 	if err != nil {
 		return nil, err
@@ -311,12 +276,12 @@ func (pp *ProjectPage) GetReader() (io.ReadCloser, error) {
 		Description: pp.proj.proj.Description,
 		URL:         purl,
 		License:     pp.proj.license,
-		Toc:         pp.toc,
+		Toc:         template.HTML(pp.toc),
 		Search:      true, // FIXME ???
 		OpenGraph:   og.GetTemplateContext(),
 		Entry: &templates.ContentEntry{
 			Title:   pp.etitle,
-			Body:    pp.body,
+			Body:    template.HTML(pp.body),
 			Project: tmpl,
 		},
 	}); err != nil {
@@ -326,7 +291,7 @@ func (pp *ProjectPage) GetReader() (io.ReadCloser, error) {
 }
 
 func (pp *ProjectPage) GetPaths() ([]string, error) {
-	if pp.proj.Immutable && pp.proj.LocalDirectory == nil {
+	if pp.proj.immutable() {
 		return nil, nil
 	}
 
@@ -337,21 +302,22 @@ func (pp *ProjectPage) GetPaths() ([]string, error) {
 
 	if pp.proj.LocalDirectory != nil {
 		if pp.proj.proj.Docs != nil {
-			rv = append(rv, filepath.Join(*pp.proj.LocalDirectory, "docs"))
+			rv = append(rv, pp.proj.localPath("docs"))
 		}
 		if pp.proj.proj.Readme != nil {
-			rv = append(rv, filepath.Join(*pp.proj.LocalDirectory, "README.md"))
+			rv = append(rv, pp.proj.localPath("README.md"))
 		}
 	}
 
 	if pp.proj.OpenGraphImageGen != nil {
 		rv = append(rv, pp.proj.OpenGraphImageGen.GetPaths()...)
 	}
+	rv = append(rv, pp.proj.statePaths(true)...)
 	return rv, nil
 }
 
 func (pp *ProjectPage) GetImmutable() bool {
-	return pp.proj.Immutable && pp.proj.LocalDirectory == nil
+	return pp.proj.immutable()
 }
 
 func (pp *ProjectPage) GetByProducts(ch chan *runner.GeneratorByProduct) {
@@ -21,8 +21,9 @@ func TestSplitFileName(t *testing.T) {
 		{"non-root page with extension stripped", "20_contact.html", false, false, 20, "contact", false},
 		{"index zero non-root errors", "0_index.md", false, false, 0, "", true},
 		{"index zero root", "0_index.md", false, true, 0, "", false},
-		{"no underscore", "badname.md", false, false, 0, "", true},
-		{"non-numeric prefix", "abc_page.md", false, false, 0, "", true},
+		{"no prefix", "badname.md", false, false, 0, "badname", false},
+		{"non-numeric prefix", "abc_page.md", false, false, 0, "abc_page", false},
+		{"empty name", "10_", false, false, 0, "", true},
 		{"nested path non-root", "docs/10_getting-started.md", false, false, 10, "getting-started", false},
 		{"nested path root", "docs/10_getting-started.md", false, true, 10, "", false},
 	}
@@ -61,6 +62,9 @@ func TestResolveUrl(t *testing.T) {
 		{"contact ppName with dot", "contact", ".", "contact/"},
 		{"about ppName with contact", "about", "contact", "../about/"},
 		{"dot ppName with about", ".", "about", "../"},
+		{"nested ppName with dot", "hardware/board", ".", "hardware/board/"},
+		{"nested ppName with nested", "hardware/board", "software/cli", "../../hardware/board/"},
+		{"dot ppName with nested", ".", "hardware/board", "../../"},
 	}
 
 	for _, tt := range tests {
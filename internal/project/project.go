@@ -5,11 +5,15 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
-	"slices"
+	"regexp"
 	"strings"
+	"time"
 
+	"rafaelmartins.com/p/website/internal/badge"
 	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/godocs"
 	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/templates"
 )
 
 type ProjectLicense struct {
@@ -18,23 +22,29 @@ type ProjectLicense struct {
 }
 
 type Project struct {
-	Owner      string
-	Repo       string
-	Licenses   []*ProjectLicense
-	RollingTag string
+	Owner        string
+	Repo         string
+	Subdirectory string
+	Licenses     []*ProjectLicense
+	RollingTag   string
 
 	Files []string
 
-	GoImport string
-	GoRepo   string
+	GoImport   string
+	GoRepo     string
+	GoPackages []string
 
-	Toc bool
+	Toc        bool
+	UnsafeHTML bool
 
 	Force             bool
 	LocalDirectory    *string
 	BaseDestination   string
 	Template          string
 	Immutable         bool
+	TTL               time.Duration
+	StateDirectory    string
+	BuildDirectory    string
 	OpenGraph         *opengraph.Config
 	OpenGraphImageGen *opengraph.OpenGraphImageGen
 
@@ -44,19 +54,113 @@ type Project struct {
 	CDocsTemplate      string
 	CDocsOpenGraph     *opengraph.Config
 
+	GoDocsEnabled     bool
+	GoDocsDestination string
+	GoDocsTemplate    string
+	GoDocsInternal    bool
+	GoDocsGOOS        string
+	GoDocsGOARCH      string
+	GoDocsOpenGraph   *opengraph.Config
+
 	DfuDestination          string
 	DfuReleaseAssetsPattern string
 
-	proj             *github.Repository
-	subdir           string
-	pages            []*ProjectPage
-	pageResolvers    []*projectPageResolver
-	url              string
-	cdocsDestination string
-	cdocsUrl         string
-	dfuDestination   string
-	dfuUrl           string
-	license          string
+	KicadEnabled bool
+
+	ReleasesDestination  string
+	ReleasesPerPage      int
+	ReleasesPerPageAtom  int
+	ReleasesTemplate     string
+	ReleasesTemplateAtom string
+	ReleasesOpenGraph    *opengraph.Config
+
+	SourceDestination string
+	SourcePatterns    []string
+	SourceTemplate    string
+	SourceOpenGraph   *opengraph.Config
+
+	IssuesDestination string
+	IssuesMilestones  bool
+	IssuesLabels      []string
+	IssuesTemplate    string
+	IssuesOpenGraph   *opengraph.Config
+
+	DownloadsEnabled       bool
+	DownloadsDestination   string
+	DownloadsReleases      int
+	DownloadsMirrorPattern string
+	DownloadsTemplate      string
+	DownloadsOpenGraph     *opengraph.Config
+
+	BadgesEnabled     bool
+	BadgesDestination string
+	BadgesCustom      []*ProjectBadge
+	BadgeRenderer     *badge.Renderer
+
+	proj                *github.Repository
+	subdir              string
+	pages               []*ProjectPage
+	nav                 []*projectNav
+	pageResolvers       []*projectPageResolver
+	url                 string
+	cdocsDestination    string
+	cdocsUrl            string
+	godocsDestination   string
+	godocsUrl           string
+	godocsPackages      []*godocs.Package
+	godocsFiles         []string
+	dfuDestination      string
+	dfuUrl              string
+	releasesDestination string
+	releasesUrl         string
+	releasesAtomUrl     string
+	sourceDestination   string
+	sourceUrl           string
+	sourceFiles         []*github.RepositoryFile
+	issuesDestination   string
+	issuesUrl           string
+	license             string
+
+	downloadsDestination   string
+	downloadsUrl           string
+	downloadsMirrorPattern *regexp.Regexp
+
+	badgesDestination string
+	badgesUrl         string
+	badges            []*ProjectBadge
+
+	starsOnly bool
+}
+
+// projects built from a subdirectory of a repository are published under the
+// repository name, followed by the subdirectory.
+func (p *Project) slug() string {
+	return path.Join(p.Repo, p.subdirectory())
+}
+
+func (p *Project) subdirectory() string {
+	if rv := path.Clean(strings.Trim(p.Subdirectory, "/")); rv != "." {
+		return rv
+	}
+	return ""
+}
+
+func (p *Project) blobUrl(ref string) string {
+	rv := "https://github.com/" + p.Owner + "/" + p.Repo + "/blob/" + ref
+	if s := p.subdirectory(); s != "" {
+		rv += "/" + s
+	}
+	return rv
+}
+
+// remote projects are pinned to a commit, their pages are never regenerated,
+// unless they are revalidated after a ttl.
+func (p *Project) immutable() bool {
+	return p.Immutable && p.LocalDirectory == nil && p.TTL <= 0
+}
+
+func (p *Project) localPath(name string) string {
+	return filepath.Join(*p.LocalDirectory, filepath.FromSlash(p.subdirectory()), filepath.FromSlash(name))
 }
 
 func (p *Project) initDfu() {
@@ -67,7 +171,7 @@ func (p *Project) initDfu() {
 
 	p.dfuUrl = ""
 	if p.DfuReleaseAssetsPattern != "" {
-		p.dfuUrl = path.Join("/", p.GetBaseDestination(), p.Repo, p.dfuDestination)
+		p.dfuUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.dfuDestination)
 		if p.dfuUrl != "/" {
 			p.dfuUrl += "/"
 		}
@@ -82,13 +186,17 @@ func (p *Project) init() error {
 		return p.reload()
 	}
 
-	proj, err := github.GetRepository(p.Owner, p.Repo, p.RollingTag, p.CDocsBaseDirectory, p.LocalDirectory)
+	if s := p.subdirectory(); s == ".." || strings.HasPrefix(s, "../") {
+		return fmt.Errorf("project: invalid subdirectory: %s", p.Subdirectory)
+	}
+
+	proj, err := github.GetRepository(p.Owner, p.Repo, p.RollingTag, p.subdirectory(), p.CDocsBaseDirectory, p.LocalDirectory)
 	if err != nil {
 		return err
 	}
 	p.proj = proj
 
-	p.url = path.Join("/", p.GetBaseDestination(), p.Repo)
+	p.url = path.Join("/", p.GetBaseDestination(), p.slug())
 	if p.url != "/" {
 		p.url += "/"
 	}
@@ -100,20 +208,74 @@ func (p *Project) init() error {
 
 	p.cdocsUrl = ""
 	if len(p.CDocsHeaders) > 0 {
-		p.cdocsUrl = path.Join("/", p.GetBaseDestination(), p.Repo, p.cdocsDestination)
+		p.cdocsUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.cdocsDestination)
 		if p.cdocsUrl != "/" {
 			p.cdocsUrl += "/"
 		}
 	}
 
+	p.godocsDestination = p.GoDocsDestination
+	if p.godocsDestination == "" {
+		p.godocsDestination = "godoc"
+	}
+
+	p.godocsUrl = ""
+	if p.GoDocsEnabled && p.GoImport != "" {
+		p.godocsUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.godocsDestination) + "/"
+	}
+
 	p.initDfu()
 
+	p.releasesDestination = p.ReleasesDestination
+	if p.releasesDestination == "" {
+		p.releasesDestination = "releases"
+	}
+
+	p.releasesUrl = ""
+	p.releasesAtomUrl = ""
+	if p.ReleasesPerPage != 0 {
+		p.releasesUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.releasesDestination) + "/"
+
+		// atom entries link to the release pages, so the feed requires them
+		if p.ReleasesPerPageAtom != 0 {
+			p.releasesAtomUrl = path.Join(p.releasesUrl, "atom.xml")
+		}
+	}
+
+	p.sourceDestination = p.SourceDestination
+	if p.sourceDestination == "" {
+		p.sourceDestination = "source"
+	}
+
+	p.sourceUrl = ""
+	if len(p.SourcePatterns) > 0 {
+		p.sourceUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.sourceDestination) + "/"
+	}
+
+	if err := p.initDownloads(); err != nil {
+		return err
+	}
+
+	p.issuesDestination = p.IssuesDestination
+	if p.issuesDestination == "" {
+		p.issuesDestination = "issues"
+	}
+
+	p.issuesUrl = ""
+	if p.IssuesMilestones || len(p.IssuesLabels) > 0 {
+		p.issuesUrl = path.Join("/", p.GetBaseDestination(), p.slug(), p.issuesDestination) + "/"
+	}
+
 	p.license = ""
 	if len(p.Licenses) > 0 {
 		p.license = p.Licenses[0].SpdxId
 	} else if p.proj.LicenseSpdx != "" {
 		p.license = p.proj.LicenseSpdx
 	}
+
+	if err := p.initBadges(); err != nil {
+		return err
+	}
 	return p.reload()
 }
 
@@ -122,46 +284,205 @@ func (p *Project) reload() error {
 		return nil
 	}
 
-	docs := p.proj.Docs
+	var (
+		docs []*projectDoc
+		nav  []*projectNav
+	)
 	subdir := "docs"
-	if len(p.proj.Docs) == 0 {
+	if len(p.proj.Docs) > 0 {
+		var err error
+		docs, nav, err = newDocs(p.proj.Docs, subdir)
+		if err != nil {
+			return err
+		}
+	} else {
 		if p.proj.Readme == nil {
 			return fmt.Errorf("project: missing readme")
 		}
-		docs = []*github.RepositoryFile{p.proj.Readme}
+		docs = []*projectDoc{{
+			file:     p.proj.Readme,
+			isReadme: true,
+			isRoot:   true,
+		}}
+		nav = []*projectNav{{doc: docs[0]}}
 		subdir = ""
 	}
 	p.subdir = subdir
+	p.nav = nav
+
+	// links from the pages to source files are resolved locally
+	if err := p.initSource(); err != nil {
+		return err
+	}
 
 	// must be filled before initializing pages!
 	p.pageResolvers = nil
-	for idx, doc := range docs {
-		res, err := newPageResolver(doc, doc == p.proj.Readme, idx == 0)
+	for _, doc := range docs {
+		res, err := newPageResolver(doc)
 		if err != nil {
 			return err
 		}
 		p.pageResolvers = append(p.pageResolvers, res)
 	}
 
-	p.pages = nil
-	for idx, doc := range docs {
-		pp, err := newPage(p, doc, doc == p.proj.Readme, idx == 0)
+	pages := map[*projectDoc]*ProjectPage{}
+	for _, doc := range docs {
+		pp, err := newPage(p, doc)
 		if err != nil {
 			return err
 		}
-		p.pages = append(p.pages, pp)
+		pages[doc] = pp
 	}
 
-	slices.SortFunc(p.pages, func(a *ProjectPage, b *ProjectPage) int {
-		if a == nil || b == nil {
-			return 0
+	// pages are ordered like the navigation, followed by the pages not listed there
+	p.pages = nil
+	walkNav(p.nav, func(n *projectNav, parents []*projectNav) bool {
+		if n.doc != nil {
+			n.page = pages[n.doc]
+			p.pages = append(p.pages, n.page)
+			delete(pages, n.doc)
 		}
-		return a.idx - b.idx
+		return true
 	})
+	for _, doc := range docs {
+		if pp, found := pages[doc]; found {
+			p.pages = append(p.pages, pp)
+		}
+	}
 	return nil
 }
 
-func (p *Project) handleImageUrl(img string, currentPage string) (string, string, error) {
+func (p *Project) getMenus(nav []*projectNav, current string, active func(pg *ProjectPage) bool) ([]*templates.ProjectContentMenu, []*templates.ProjectContentMenu) {
+	menus := []*templates.ProjectContentMenu{}
+	var trail []*templates.ProjectContentMenu
+
+	for _, n := range nav {
+		menu := &templates.ProjectContentMenu{
+			Title: n.title,
+		}
+		if n.page != nil {
+			menu.URL = n.page.resolver.resolveUrl(current)
+			menu.Active = active != nil && active(n.page)
+			if menu.Title == "" {
+				menu.Title = n.page.menu
+			}
+		}
+
+		children, t := p.getMenus(n.children, current, active)
+		menu.Children = children
+
+		// sections without index pages link to their first page
+		if menu.URL == "" {
+			for _, child := range children {
+				if child.URL != "" {
+					menu.URL = child.URL
+					break
+				}
+			}
+		}
+		if menu.Active {
+			trail = []*templates.ProjectContentMenu{menu}
+		} else if t != nil {
+			// sections are active if any of their descendants is active
+			menu.Active = true
+			trail = append([]*templates.ProjectContentMenu{menu}, t...)
+		}
+		menus = append(menus, menu)
+	}
+	return menus, trail
+}
+
+func (p *Project) getTemplateEntry(current string, active func(pg *ProjectPage) bool) *templates.ProjectContentEntry {
+	rv := &templates.ProjectContentEntry{
+		Owner:        p.Owner,
+		Repo:         p.Repo,
+		Name:         p.slug(),
+		Subdirectory: p.subdirectory(),
+		URL:          p.proj.HomepageUrl,
+		Description:  p.proj.Description,
+		GoImport:     p.GoImport,
+		GoRepo:       p.GoRepo,
+		CDocsURL:     p.cdocsUrl,
+		GoDocsURL:    p.godocsUrl,
+		ReleasesURL:  p.releasesUrl,
+		AtomURL:      p.releasesAtomUrl,
+		SourceURL:    p.sourceUrl,
+		IssuesURL:    p.issuesUrl,
+		DownloadsURL: p.downloadsUrl,
+		Badges:       p.getBadgesTemplateEntries(),
+		Stars:        p.proj.Stars,
+		Watching:     p.proj.Watchers,
+		Forks:        p.proj.Forks,
+	}
+
+	for _, lic := range p.Licenses {
+		rv.Licenses = append(rv.Licenses, &templates.ProjectContentLicense{
+			SpdxId: lic.SpdxId,
+			Title:  lic.Title,
+		})
+	}
+
+	menus, trail := p.getMenus(p.nav, current, active)
+	rv.Menus = menus
+
+	// breadcrumbs are only useful for nested pages
+	if len(trail) > 1 {
+		for _, pg := range p.pages {
+			if pg.isRoot {
+				rv.Breadcrumbs = append(rv.Breadcrumbs, &templates.ProjectContentMenu{
+					URL:   pg.resolver.resolveUrl(current),
+					Title: p.slug(),
+				})
+			}
+		}
+		for i, menu := range trail {
+			rv.Breadcrumbs = append(rv.Breadcrumbs, &templates.ProjectContentMenu{
+				Active: i == len(trail)-1,
+				URL:    menu.URL,
+				Title:  menu.Title,
+			})
+		}
+	}
+
+	if p.releasesUrl != "" {
+		res := &projectPageResolver{name: p.releasesDestination}
+		rv.Menus = append(rv.Menus, &templates.ProjectContentMenu{
+			Active: active == nil,
+			URL:    res.resolveUrl(current),
+			Title:  "Releases",
+		})
+	}
+
+	if p.downloadsUrl != "" {
+		res := &projectPageResolver{name: p.downloadsDestination}
+		rv.Menus = append(rv.Menus, &templates.ProjectContentMenu{
+			Active: current == p.downloadsDestination,
+			URL:    res.resolveUrl(current),
+			Title:  "Downloads",
+		})
+	}
+
+	if p.issuesUrl != "" {
+		res := &projectPageResolver{name: p.issuesDestination}
+		rv.Menus = append(rv.Menus, &templates.ProjectContentMenu{
+			Active: current == p.issuesDestination,
+			URL:    res.resolveUrl(current),
+			Title:  "Issues",
+		})
+	}
+
+	if p.sourceUrl != "" {
+		res := &projectPageResolver{name: p.sourceDestination}
+		rv.Menus = append(rv.Menus, &templates.ProjectContentMenu{
+			Active: current == p.sourceDestination,
+			URL:    res.resolveUrl(current),
+			Title:  "Source",
+		})
+	}
+	return rv
+}
+
+func (p *Project) handleImageUrl(img string, srcDir string, currentPage string) (string, string, error) {
 	if img == "" {
 		return "", "", nil
 	}
@@ -181,7 +502,7 @@ func (p *Project) handleImageUrl(img string, currentPage string) (string, string
 
 	v := strings.TrimPrefix(u.Path, "/")
 	if !path.IsAbs(u.Path) {
-		v = path.Join(p.subdir, u.Path)
+		v = path.Join(srcDir, u.Path)
 		if v == ".." || strings.HasPrefix(v, "../") {
 			return "", "", fmt.Errorf("project: path traversal not allowed: %s", img)
 		}
@@ -194,7 +515,7 @@ func (p *Project) handleImageUrl(img string, currentPage string) (string, string
 	return v, f, nil
 }
 
-func (p *Project) handleLinkUrl(link string, currentPage string) (bool, string, error) {
+func (p *Project) handleLinkUrl(link string, srcDir string, currentPage string) (bool, string, error) {
 	if link == "" {
 		return false, "", nil
 	}
@@ -212,7 +533,7 @@ func (p *Project) handleLinkUrl(link string, currentPage string) (bool, string,
 		return false, after, nil
 	}
 
-	v := path.Join(p.subdir, u.Path)
+	v := path.Join(srcDir, u.Path)
 	if path.IsAbs(u.Path) {
 		v = strings.TrimPrefix(u.Path, "/")
 	}
@@ -223,6 +544,17 @@ func (p *Project) handleLinkUrl(link string, currentPage string) (bool, string,
 			return false, pg.resolveUrl(currentPage), nil
 		}
 	}
+
+	if su, ok := p.getSourceUrl(v); ok {
+		current := path.Clean(currentPage)
+		if current != "." {
+			su = strings.Repeat("../", strings.Count(current, "/")+1) + su
+		}
+		if u.Fragment != "" {
+			su += "#" + u.Fragment
+		}
+		return false, su, nil
+	}
 	return true, v, nil
 }
 
@@ -94,8 +94,8 @@ func TestHandleImageUrl(t *testing.T) {
 
 	for _, tt := range args {
 		t.Run(tt.name, func(t *testing.T) {
-			proj := &Project{subdir: tt.subdir}
-			rv1, rv2, err := proj.handleImageUrl(tt.img, tt.currentPage)
+			proj := &Project{}
+			rv1, rv2, err := proj.handleImageUrl(tt.img, tt.subdir, tt.currentPage)
 			if tt.hasErr {
 				if err == nil {
 					t.Error("expected error but got none")
@@ -193,7 +193,7 @@ func TestHandleLinkUrl(t *testing.T) {
 
 		{"guide.md from page", "guide.md", "docs", "page", pageResolvers, false, "../guide/"},
 		{"page.md from guide", "page.md", "docs", "guide", pageResolvers, false, "../page/"},
-		{"index.md from nested/deep", "index.md", "docs", "nested/deep", pageResolvers, false, "../"},
+		{"index.md from nested/deep", "index.md", "docs", "nested/deep", pageResolvers, false, "../../"},
 		{"nested/deep.md from asd", "nested/deep.md", "docs", "asd", pageResolvers, true, "docs/nested/deep.md"},
 		{"nested/deep.md from nested/deep", "nested/deep.md", "docs", "nested/deep", pageResolvers, true, "docs/nested/deep.md"},
 
@@ -205,8 +205,8 @@ func TestHandleLinkUrl(t *testing.T) {
 
 	for _, tt := range args {
 		t.Run(tt.name, func(t *testing.T) {
-			proj := &Project{subdir: tt.subdir, pageResolvers: tt.pages}
-			gh, rv, err := proj.handleLinkUrl(tt.link, tt.currentPage)
+			proj := &Project{pageResolvers: tt.pages}
+			gh, rv, err := proj.handleLinkUrl(tt.link, tt.subdir, tt.currentPage)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -0,0 +1,314 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark/parser"
+	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/markdown"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+var reReleaseIdFilter = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+func releaseId(tag string) string {
+	rv := reReleaseIdFilter.ReplaceAllString(tag, " ")
+	rv = strings.TrimSpace(rv)
+	rv = strings.ReplaceAll(rv, " ", "-")
+	return "release-" + strings.ToLower(rv)
+}
+
+type releases struct {
+	proj     *Project
+	atom     bool
+	page     int
+	total    int
+	releases []*github.RepositoryRelease
+
+	og *opengraph.OpenGraph
+}
+
+func (r *releases) slug() string {
+	if r.atom || r.page == 0 {
+		return ""
+	}
+	return path.Join("page", strconv.FormatInt(int64(r.page), 10))
+}
+
+func (r *releases) GetDestination() string {
+	if r.atom {
+		return filepath.Join(r.proj.slug(), r.proj.releasesDestination, "atom.xml")
+	}
+	return filepath.Join(r.proj.slug(), r.proj.releasesDestination, filepath.FromSlash(r.slug()), "index.html")
+}
+
+func (r *releases) GetGenerator() (runner.Generator, error) {
+	return r, nil
+}
+
+func (*releases) GetID() string {
+	return "RELEASES"
+}
+
+func (r *releases) getTemplate() string {
+	if r.atom {
+		rv := r.proj.ReleasesTemplateAtom
+		if rv == "" {
+			rv = "atom.xml"
+		}
+		return rv
+	}
+
+	rv := r.proj.ReleasesTemplate
+	if rv == "" {
+		rv = "project-releases.html"
+	}
+	return rv
+}
+
+func (r *releases) pagination() *templates.ContentPagination {
+	rv := &templates.ContentPagination{
+		Enabled: r.proj.ReleasesPerPage > 0,
+		BaseURL: path.Join(r.proj.releasesUrl, "page"),
+		AtomURL: r.proj.releasesAtomUrl,
+		Current: r.page,
+		Total:   r.total,
+	}
+	if rv.Current == 0 {
+		rv.Current = 1
+	}
+	if rv.Current > 1 {
+		rv.LinkPrevious = path.Join(rv.BaseURL, strconv.FormatInt(int64(rv.Current-1), 10)) + "/"
+	}
+	if rv.Current < rv.Total {
+		rv.LinkNext = path.Join(rv.BaseURL, strconv.FormatInt(int64(rv.Current+1), 10)) + "/"
+	}
+	return rv
+}
+
+func (r *releases) GetReader() (io.ReadCloser, error) {
+	// paginated pages are nested deeper than the regular project pages, use
+	// absolute urls everywhere to avoid dealing with relative paths.
+	current := r.proj.releasesDestination
+	absurl := path.Join(r.proj.url, current)
+
+	tmpl := r.proj.getTemplateEntry(current, nil)
+	absoluteMenus(absurl, tmpl.Menus)
+
+	latest := ""
+	if r.proj.proj.LatestRelease != nil {
+		latest = r.proj.proj.LatestRelease.Tag
+	}
+
+	entries := []*templates.ContentEntry{}
+	atomUpdated := time.Unix(0, 0)
+	for _, release := range r.releases {
+		body := ""
+		if release.Description != "" {
+			pc := parser.NewContext()
+			pc.Set(pcProjectKey, r.proj)
+			pc.Set(pcBaseUrlKey, r.proj.blobUrl(release.Tag))
+			pc.Set(markdown.PcReferences, &markdown.References{Owner: r.proj.Owner, Repo: r.proj.Repo})
+			pc.Set(pcCurrentPageKey, current)
+			pc.Set(pcAbsoluteUrlKey, absurl)
+			r.proj.setSanitize(pc, "release "+release.Tag)
+
+			var err error
+			_, body, err = markdown.Render(gmMarkdown, []byte(release.Description), pc)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		name := release.Name
+		if name == "" {
+			name = release.Tag
+		}
+
+		rel := &templates.ProjectContentRelease{
+			ID:         releaseId(release.Tag),
+			Name:       name,
+			Tag:        release.Tag,
+			Body:       template.HTML(body),
+			URL:        release.Url,
+			Published:  release.Published,
+			Prerelease: release.Prerelease,
+			Latest:     release.Tag == latest,
+		}
+		for _, asset := range release.Assets {
+			rel.Files = append(rel.Files,
+				&templates.ProjectContentLatestReleaseFile{
+					File:          asset.Name,
+					URL:           r.proj.getAssetUrl(release.Tag, &asset),
+					Size:          asset.Size,
+					DownloadCount: asset.DownloadCount,
+				},
+			)
+		}
+		slices.SortFunc(rel.Files, func(a *templates.ProjectContentLatestReleaseFile, b *templates.ProjectContentLatestReleaseFile) int {
+			return strings.Compare(a.File, b.File)
+		})
+		tmpl.Releases = append(tmpl.Releases, rel)
+
+		entries = append(entries, &templates.ContentEntry{
+			URL:   r.proj.getReleaseUrl(release.Tag),
+			Title: fmt.Sprintf("%s %s", r.proj.slug(), name),
+			Body:  template.HTML(body),
+			Post: &templates.PostContentEntry{
+				Published: release.Published,
+			},
+		})
+		if atomUpdated.Before(release.Published) {
+			atomUpdated = release.Published
+		}
+	}
+
+	title := fmt.Sprintf("%s: Releases", r.proj.slug())
+	description := fmt.Sprintf("Release history of %s", r.proj.slug())
+	if r.proj.proj.Description != "" {
+		description = fmt.Sprintf("%s: %s", description, r.proj.proj.Description)
+	}
+
+	ctx := &templates.ContentContext{
+		Title:       title,
+		Description: description,
+		URL:         path.Join(r.proj.releasesUrl, r.slug()) + "/",
+		License:     r.proj.license,
+		Search:      !r.atom,
+		Pagination:  r.pagination(),
+		Entry: &templates.ContentEntry{
+			Title:   title,
+			Project: tmpl,
+		},
+	}
+
+	if r.atom {
+		ctx.URL = r.proj.releasesUrl
+		ctx.Entries = entries
+		ctx.Atom = &templates.AtomContentEntry{
+			Updated: atomUpdated,
+		}
+	} else {
+		og, err := opengraph.New(r.proj.OpenGraphImageGen, r.page != 0, r.proj.releasesUrl, title, description, r.proj.ReleasesOpenGraph, "", "", nil)
+		if err != nil {
+			return nil, err
+		}
+		r.og = og
+		ctx.OpenGraph = og.GetTemplateContext()
+	}
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, r.getTemplate(), nil, nil, ctx); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (r *releases) GetPaths() ([]string, error) {
+	if r.proj.immutable() {
+		return nil, nil
+	}
+
+	rv, err := templates.GetPaths(r.getTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.atom && r.proj.OpenGraphImageGen != nil {
+		rv = append(rv, r.proj.OpenGraphImageGen.GetPaths()...)
+	}
+	rv = append(rv, r.proj.statePaths(false)...)
+	return rv, nil
+}
+
+func (r *releases) GetImmutable() bool {
+	return r.proj.immutable()
+}
+
+func (r *releases) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if r.og != nil {
+			r.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
+
+func (p *Project) getReleaseUrl(tag string) string {
+	for idx, release := range p.proj.Releases {
+		if release.Tag != tag {
+			continue
+		}
+
+		rv := p.releasesUrl
+		if p.ReleasesPerPage > 0 && idx >= p.ReleasesPerPage {
+			rv = path.Join(rv, "page", strconv.FormatInt(int64(idx/p.ReleasesPerPage+1), 10)) + "/"
+		}
+		return rv + "#" + releaseId(tag)
+	}
+	return ""
+}
+
+func (p *Project) getReleasesTasks() []*runner.Task {
+	if p.ReleasesPerPage == 0 {
+		return nil
+	}
+
+	rv := []*runner.Task{}
+	if p.ReleasesPerPageAtom != 0 {
+		rels := p.proj.Releases
+		if p.ReleasesPerPageAtom > 0 && len(rels) > p.ReleasesPerPageAtom {
+			rels = rels[:p.ReleasesPerPageAtom]
+		}
+		rv = append(rv, runner.NewTask(p, &releases{
+			proj:     p,
+			atom:     true,
+			releases: rels,
+		}))
+	}
+
+	ppp := p.ReleasesPerPage
+	if ppp < 0 {
+		ppp = max(len(p.proj.Releases), 1)
+	}
+	total := int(math.Ceil(float64(len(p.proj.Releases)) / float64(ppp)))
+
+	if total == 0 {
+		return append(rv, runner.NewTask(p, &releases{
+			proj:  p,
+			total: 1,
+		}))
+	}
+
+	page := 1
+	for chk := range slices.Chunk(p.proj.Releases, ppp) {
+		if page == 1 {
+			rv = append(rv, runner.NewTask(p, &releases{
+				proj:     p,
+				total:    total,
+				releases: chk,
+			}))
+		}
+		rv = append(rv, runner.NewTask(p, &releases{
+			proj:     p,
+			page:     page,
+			total:    total,
+			releases: chk,
+		}))
+		page++
+	}
+	return rv
+}
@@ -0,0 +1,51 @@
+package project
+
+import (
+	"testing"
+)
+
+func TestReleaseId(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{"simple", "v1", "release-v1"},
+		{"dots", "v1.2.3", "release-v1-2-3"},
+		{"uppercase", "V1.0-RC1", "release-v1-0-rc1"},
+		{"slashes", "firmware/v1.0", "release-firmware-v1-0"},
+		{"surrounding symbols", "-v1-", "release-v1"},
+		{"underscores kept", "v1_0", "release-v1_0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := releaseId(tt.tag); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbsoluteUrl(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		u    string
+		want string
+	}{
+		{"current dir", "/projects/foo/releases", "./", "/projects/foo/releases/"},
+		{"parent dir", "/projects/foo/releases", "../", "/projects/foo/"},
+		{"sibling page", "/projects/foo/releases", "../about/", "/projects/foo/about/"},
+		{"file", "/projects/foo/releases", "../docs/image.png", "/projects/foo/docs/image.png"},
+		{"root", "/releases", "../", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := absoluteUrl(tt.base, tt.u); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
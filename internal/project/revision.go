@@ -0,0 +1,145 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/utils"
+)
+
+const (
+	revisionFile = "revision.json"
+	starsFile    = "stars.json"
+)
+
+type projectRevision struct {
+	Head          string `json:"head"`
+	LatestRelease string `json:"latest-release,omitempty"`
+}
+
+type projectStars struct {
+	Stars int `json:"stars"`
+}
+
+// state files are kept outside of the build directory, and only written when
+// their content changes, so that their modification times can be used as
+// dependencies of the generated files.
+func (p *Project) statePath(name string) string {
+	return filepath.Join(p.StateDirectory, p.GetBaseDestination(), p.slug(), name)
+}
+
+// statePaths returns the state files the pages of remote projects depend on,
+// if they are revalidated.
+func (p *Project) statePaths(stars bool) []string {
+	if p.TTL <= 0 || p.LocalDirectory != nil {
+		return nil
+	}
+
+	rv := []string{p.statePath(revisionFile)}
+	if stars {
+		rv = append(rv, p.statePath(starsFile))
+	}
+	return rv
+}
+
+func (p *Project) readState(name string, v any) (bool, error) {
+	data, err := os.ReadFile(p.statePath(name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// broken state files are just replaced
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *Project) writeState(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileIfChanged(p.statePath(name), data)
+}
+
+func (p *Project) updateState() error {
+	if p.TTL <= 0 || p.LocalDirectory != nil {
+		return nil
+	}
+
+	rev := p.proj.GetRevision()
+	if err := p.writeState(revisionFile, &projectRevision{
+		Head:          rev.Head,
+		LatestRelease: rev.LatestRelease,
+	}); err != nil {
+		return err
+	}
+	return p.writeState(starsFile, &projectStars{
+		Stars: rev.Stars,
+	})
+}
+
+// revisionChanged reports if the repository contents changed since the last
+// build, and if only the star count changed.
+func (p *Project) revisionChanged(rev *github.RepositoryRevision) (bool, bool, error) {
+	oldRev := projectRevision{}
+	found, err := p.readState(revisionFile, &oldRev)
+	if err != nil {
+		return false, false, err
+	}
+	if !found || oldRev.Head != rev.Head || oldRev.LatestRelease != rev.LatestRelease {
+		return true, false, nil
+	}
+
+	oldStars := projectStars{}
+	found, err = p.readState(starsFile, &oldStars)
+	if err != nil {
+		return false, false, err
+	}
+	return false, !found || oldStars.Stars != rev.Stars, nil
+}
+
+// Revalidate compares the current revision of the repository with the one
+// used to build the existing pages. if the contents changed, the repository is
+// fetched again and the outdated pages are regenerated. if only the star
+// count changed, just the pages showing it are regenerated.
+func (p *Project) Revalidate() (bool, error) {
+	rev, err := github.GetRepositoryRevision(p.Owner, p.Repo)
+	if err != nil {
+		return false, err
+	}
+
+	changed, stars, err := p.revisionChanged(rev)
+	if err != nil {
+		return false, err
+	}
+	p.starsOnly = !changed && stars
+	if !changed && !stars {
+		return false, nil
+	}
+
+	if changed {
+		log.Printf("project %s/%s changed, revalidating: %s", p.Owner, p.Repo, p.slug())
+	} else {
+		log.Printf("project %s/%s stars changed, revalidating: %s", p.Owner, p.Repo, p.slug())
+	}
+
+	// remote repositories are cached, including some of the derived data,
+	// that does not depend on the star count
+	github.ExpireRepository(p.Owner, p.Repo, p.RollingTag, rev)
+	p.proj = nil
+	if changed {
+		p.sourceFiles = nil
+		p.godocsPackages = nil
+	}
+	return true, nil
+}
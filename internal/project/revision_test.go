@@ -0,0 +1,151 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func TestRevisionChanged(t *testing.T) {
+	dir := t.TempDir()
+	proj := &Project{
+		Repo:           "foo",
+		Subdirectory:   "bar",
+		StateDirectory: dir,
+	}
+	rev := &github.RepositoryRevision{
+		Head:          "0123456789abcdef",
+		LatestRelease: "v1.2.3",
+		Stars:         42,
+	}
+
+	changed, stars, err := proj.revisionChanged(rev)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !changed || stars {
+		t.Errorf("missing revision file must be reported as changed")
+	}
+
+	fn := filepath.Join(dir, "projects", "foo", "bar", revisionFile)
+	sfn := filepath.Join(dir, "projects", "foo", "bar", starsFile)
+	if err := os.MkdirAll(filepath.Dir(fn), 0777); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		data    string
+		stars   string
+		changed bool
+		starsc  bool
+	}{
+		{"same", `{"head":"0123456789abcdef","latest-release":"v1.2.3"}`, `{"stars":42}`, false, false},
+		{"head", `{"head":"fedcba9876543210","latest-release":"v1.2.3"}`, `{"stars":42}`, true, false},
+		{"release", `{"head":"0123456789abcdef","latest-release":"v1.2.2"}`, `{"stars":42}`, true, false},
+		{"stars", `{"head":"0123456789abcdef","latest-release":"v1.2.3"}`, `{"stars":41}`, false, true},
+		{"missing-stars", `{"head":"0123456789abcdef","latest-release":"v1.2.3"}`, ``, false, true},
+		{"broken", `{"head":`, `{"stars":42}`, true, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(fn, []byte(tt.data), 0666); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			os.Remove(sfn)
+			if tt.stars != "" {
+				if err := os.WriteFile(sfn, []byte(tt.stars), 0666); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			changed, stars, err := proj.revisionChanged(rev)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if changed != tt.changed || stars != tt.starsc {
+				t.Errorf("got changed=%t stars=%t, want %t %t", changed, stars, tt.changed, tt.starsc)
+			}
+		})
+	}
+}
+
+func TestWriteState(t *testing.T) {
+	dir := t.TempDir()
+	proj := &Project{
+		Repo:           "foo",
+		StateDirectory: dir,
+	}
+
+	if err := proj.writeState(starsFile, &projectStars{Stars: 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fn := filepath.Join(dir, "projects", "foo", starsFile)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(fn, old, old); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// unchanged state must not be touched
+	if err := proj.writeState(starsFile, &projectStars{Stars: 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	st, err := os.Stat(fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !st.ModTime().Equal(old) {
+		t.Errorf("unchanged state file was rewritten")
+	}
+
+	if err := proj.writeState(starsFile, &projectStars{Stars: 2}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	st, err = os.Stat(fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !st.ModTime().After(old) {
+		t.Errorf("changed state file was not rewritten")
+	}
+}
+
+func TestStatePaths(t *testing.T) {
+	proj := &Project{
+		Repo:           "foo",
+		StateDirectory: "state",
+	}
+	if p := proj.statePaths(true); p != nil {
+		t.Errorf("project without ttl must not depend on state: %v", p)
+	}
+
+	proj.TTL = time.Hour
+	if p := proj.statePaths(false); !slices.Equal(p, []string{filepath.Join("state", "projects", "foo", revisionFile)}) {
+		t.Errorf("unexpected state paths: %v", p)
+	}
+	if p := proj.statePaths(true); len(p) != 2 || p[1] != filepath.Join("state", "projects", "foo", starsFile) {
+		t.Errorf("unexpected state paths: %v", p)
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	proj := &Project{Immutable: true}
+	if !proj.immutable() {
+		t.Errorf("remote project must be immutable")
+	}
+
+	proj.TTL = time.Hour
+	if proj.immutable() {
+		t.Errorf("revalidated project must not be immutable")
+	}
+
+	dir := "/tmp"
+	proj = &Project{Immutable: true, LocalDirectory: &dir}
+	if proj.immutable() {
+		t.Errorf("local project must not be immutable")
+	}
+}
@@ -0,0 +1,356 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"rafaelmartins.com/p/website/internal/github"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+var (
+	chSourceFormatter = html.New(
+		html.WithClasses(false),
+		html.WithLineNumbers(true),
+		html.WithLinkableLineNumbers(true, "L"),
+	)
+	chSourceStyle = styles.Get("github")
+)
+
+func highlightSource(name string, data []byte) (string, string, error) {
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Analyse(string(data))
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	iter, err := lexer.Tokenise(nil, string(data))
+	if err != nil {
+		return "", "", err
+	}
+
+	b := bytes.Buffer{}
+	if err := chSourceFormatter.Format(&b, chSourceStyle, iter); err != nil {
+		return "", "", err
+	}
+	return b.String(), lexer.Config().Name, nil
+}
+
+// patterns are matched with path.Match against the full path of the files.
+// patterns ending with "/**" match everything inside the directory.
+func matchSourcePattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if dir, found := strings.CutSuffix(pattern, "/**"); found {
+			if strings.HasPrefix(name, dir+"/") {
+				return true
+			}
+			continue
+		}
+		if m, err := path.Match(pattern, name); err == nil && m {
+			return true
+		}
+	}
+	return false
+}
+
+type source struct {
+	proj *Project
+	name string
+	file *github.RepositoryFile
+	og   *opengraph.OpenGraph
+}
+
+func (s *source) url() string {
+	if s.file != nil {
+		return path.Join(s.proj.sourceUrl, s.name) + ".html"
+	}
+	if s.name == "" {
+		return s.proj.sourceUrl
+	}
+	return path.Join(s.proj.sourceUrl, s.name) + "/"
+}
+
+func (s *source) GetDestination() string {
+	if s.file != nil {
+		return filepath.Join(s.proj.slug(), s.proj.sourceDestination, filepath.FromSlash(s.name)+".html")
+	}
+	return filepath.Join(s.proj.slug(), s.proj.sourceDestination, filepath.FromSlash(s.name), "index.html")
+}
+
+func (s *source) GetGenerator() (runner.Generator, error) {
+	return s, nil
+}
+
+func (*source) GetID() string {
+	return "SOURCE"
+}
+
+func (s *source) getTemplate() string {
+	rv := s.proj.SourceTemplate
+	if rv == "" {
+		rv = "source.html"
+	}
+	return rv
+}
+
+func (s *source) GetReader() (io.ReadCloser, error) {
+	// source pages are nested deeper than the regular project pages, use
+	// absolute urls everywhere to avoid dealing with relative paths.
+	current := s.proj.sourceDestination
+	absurl := path.Join(s.proj.url, current)
+
+	tmpl := s.proj.getTemplateEntry(current, func(pg *ProjectPage) bool {
+		return false
+	})
+	absoluteMenus(absurl, tmpl.Menus)
+
+	sctx := &templates.SourceContentEntry{
+		Path: s.name,
+		Breadcrumbs: []*templates.ProjectContentMenu{
+			{
+				Title:  s.proj.slug(),
+				URL:    s.proj.sourceUrl,
+				Active: s.name == "",
+			},
+		},
+	}
+
+	parts := []string{}
+	for part := range strings.SplitSeq(s.name, "/") {
+		if part == "" {
+			continue
+		}
+		parts = append(parts, part)
+		u := path.Join(s.proj.sourceUrl, path.Join(parts...)) + "/"
+		if len(parts) == strings.Count(s.name, "/")+1 && s.file != nil {
+			u = s.url()
+		}
+		sctx.Breadcrumbs = append(sctx.Breadcrumbs, &templates.ProjectContentMenu{
+			Title:  part,
+			URL:    u,
+			Active: len(parts) == strings.Count(s.name, "/")+1,
+		})
+	}
+
+	title := fmt.Sprintf("%s: Source", s.proj.slug())
+	description := fmt.Sprintf("Source code of %s", s.proj.slug())
+	if s.name != "" {
+		title = fmt.Sprintf("%s: %s", s.proj.slug(), s.name)
+		description = fmt.Sprintf("%s: %s", description, s.name)
+	}
+
+	if s.file != nil {
+		data, err := s.file.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		code, lang, err := highlightSource(s.name, data)
+		if err != nil {
+			return nil, err
+		}
+		sctx.Code = template.HTML(code)
+		sctx.Language = lang
+		sctx.Lines = bytes.Count(data, []byte{'\n'})
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			sctx.Lines++
+		}
+		sctx.Size = int64(len(data))
+		sctx.RawURL = path.Join(s.proj.url, s.name)
+		sctx.SourceURL = s.proj.blobUrl(s.proj.proj.Head) + "/" + s.name
+	} else {
+		dirs := map[string]bool{}
+		for _, f := range s.proj.sourceFiles {
+			rel, found := strings.CutPrefix(f.Name, s.name+"/")
+			if s.name == "" {
+				rel, found = f.Name, true
+			}
+			if !found {
+				continue
+			}
+
+			if dir, _, isDir := strings.Cut(rel, "/"); isDir {
+				if !dirs[dir] {
+					dirs[dir] = true
+					sctx.Entries = append(sctx.Entries, &templates.SourceContentFile{
+						Name:  dir,
+						URL:   path.Join(s.proj.sourceUrl, s.name, dir) + "/",
+						IsDir: true,
+					})
+				}
+				continue
+			}
+
+			data, err := f.Read()
+			if err != nil {
+				return nil, err
+			}
+			sctx.Entries = append(sctx.Entries, &templates.SourceContentFile{
+				Name: rel,
+				URL:  path.Join(s.proj.sourceUrl, f.Name) + ".html",
+				Size: int64(len(data)),
+			})
+		}
+		slices.SortStableFunc(sctx.Entries, func(a *templates.SourceContentFile, b *templates.SourceContentFile) int {
+			if a.IsDir != b.IsDir {
+				if a.IsDir {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	// all the source pages share the opengraph image of the index page
+	og, err := opengraph.New(s.proj.OpenGraphImageGen, s.name != "", s.proj.sourceUrl, title, description, s.proj.SourceOpenGraph, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.og = og
+
+	buf := &bytes.Buffer{}
+	if err := templates.Execute(buf, s.getTemplate(), nil, nil, &templates.ContentContext{
+		Title:       title,
+		Description: description,
+		URL:         s.url(),
+		License:     s.proj.license,
+		OpenGraph:   og.GetTemplateContext(),
+		Entry: &templates.ContentEntry{
+			Title:   title,
+			Project: tmpl,
+			Source:  sctx,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(buf), nil
+}
+
+func (s *source) GetPaths() ([]string, error) {
+	if s.proj.immutable() {
+		return nil, nil
+	}
+
+	rv, err := templates.GetPaths(s.getTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.proj.LocalDirectory != nil {
+		rv = append(rv, s.proj.localPath(s.name))
+	}
+
+	if s.proj.OpenGraphImageGen != nil {
+		rv = append(rv, s.proj.OpenGraphImageGen.GetPaths()...)
+	}
+	rv = append(rv, s.proj.statePaths(false)...)
+	return rv, nil
+}
+
+func (s *source) GetImmutable() bool {
+	return s.proj.immutable()
+}
+
+func (s *source) GetByProducts(ch chan *runner.GeneratorByProduct) {
+	if ch != nil {
+		if s.og != nil {
+			s.og.GenerateByProduct(ch, "")
+		}
+		close(ch)
+	}
+}
+
+func (p *Project) initSource() error {
+	if len(p.SourcePatterns) == 0 {
+		p.sourceFiles = nil
+		return nil
+	}
+
+	// remote repositories are pinned to a commit, no need to fetch the sources again
+	if p.sourceFiles != nil && p.LocalDirectory == nil {
+		return nil
+	}
+
+	filter := func(name string) bool {
+		return matchSourcePattern(p.SourcePatterns, name)
+	}
+
+	// the names are enough to link the sources from the pages showing the
+	// star count, no need to fetch the contents
+	var files []*github.RepositoryFile
+	if p.starsOnly {
+		files = p.proj.ListTreeFiles(filter)
+	} else {
+		var err error
+		files, err = p.proj.ListFiles(filter)
+		if err != nil {
+			return err
+		}
+	}
+	slices.SortFunc(files, func(a *github.RepositoryFile, b *github.RepositoryFile) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	p.sourceFiles = files
+	return nil
+}
+
+func (p *Project) getSourceTasks() ([]*runner.Task, []string) {
+	rv := []*runner.Task{}
+	files := []string{}
+	dirs := map[string]bool{"": true}
+	for _, f := range p.sourceFiles {
+		rv = append(rv, runner.NewTask(p, &source{
+			proj: p,
+			name: f.Name,
+			file: f,
+		}))
+		files = append(files, f.Name)
+
+		for d := path.Dir(f.Name); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+
+	for _, d := range slices.Sorted(maps.Keys(dirs)) {
+		rv = append(rv, runner.NewTask(p, &source{
+			proj: p,
+			name: d,
+		}))
+	}
+	return rv, files
+}
+
+// getSourceUrl returns the url of the source page for the given file or
+// directory, relative to the project root, if it is part of the source browser.
+func (p *Project) getSourceUrl(name string) (string, bool) {
+	if p.sourceUrl == "" {
+		return "", false
+	}
+
+	for _, f := range p.sourceFiles {
+		if f.Name == name {
+			return path.Join(p.sourceDestination, name) + ".html", true
+		}
+		if name == "." || strings.HasPrefix(f.Name, name+"/") {
+			return path.Join(p.sourceDestination, name) + "/", true
+		}
+	}
+	return "", false
+}
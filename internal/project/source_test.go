@@ -0,0 +1,92 @@
+package project
+
+import (
+	"testing"
+
+	"rafaelmartins.com/p/website/internal/github"
+)
+
+func TestMatchSourcePattern(t *testing.T) {
+	patterns := []string{"*.go", "examples/**", "firmware/*.c"}
+
+	args := []struct {
+		name string
+		exp  bool
+	}{
+		{"main.go", true},
+		{"foo/main.go", false},
+		{"examples/blink/main.c", true},
+		{"examples", false},
+		{"examplesfoo/main.c", false},
+		{"firmware/main.c", true},
+		{"firmware/main.h", false},
+		{"firmware/drivers/usb.c", false},
+	}
+
+	for _, tt := range args {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchSourcePattern(patterns, tt.name); got != tt.exp {
+				t.Errorf("got %v, want %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestSourceLinkUrl(t *testing.T) {
+	proj := &Project{
+		sourceDestination: "source",
+		sourceUrl:         "/projects/foo/source/",
+		sourceFiles: []*github.RepositoryFile{
+			{Name: "examples/blink/main.c"},
+			{Name: "main.go"},
+		},
+	}
+
+	args := []struct {
+		name        string
+		link        string
+		subdir      string
+		currentPage string
+		expGH       bool
+		expRV       string
+	}{
+		{"file", "main.go", "", ".", false, "source/main.go.html"},
+		{"file from docs", "../main.go", "docs", ".", false, "source/main.go.html"},
+		{"file from page", "/main.go", "docs", "guide", false, "../source/main.go.html"},
+		{"file from nested page", "/main.go", "docs", "nested/deep", false, "../../source/main.go.html"},
+		{"file with line", "main.go#L10", "", ".", false, "source/main.go.html#L10"},
+		{"directory", "examples", "", ".", false, "source/examples/"},
+		{"nested directory", "examples/blink/", "", ".", false, "source/examples/blink/"},
+		{"root", ".", "", ".", false, "source/"},
+		{"not selected", "README.md", "", ".", true, "README.md"},
+		{"partial directory name", "exam", "", ".", true, "exam"},
+	}
+
+	for _, tt := range args {
+		t.Run(tt.name, func(t *testing.T) {
+			gh, rv, err := proj.handleLinkUrl(tt.link, tt.subdir, tt.currentPage)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gh != tt.expGH {
+				t.Errorf("gh mismatch: got %v, want %v", gh, tt.expGH)
+			}
+			if rv != tt.expRV {
+				t.Errorf("bad rv: got %q, want %q", rv, tt.expRV)
+			}
+		})
+	}
+}
+
+func TestHighlightSource(t *testing.T) {
+	code, lang, err := highlightSource("main.go", []byte("package main\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lang != "Go" {
+		t.Errorf("got language %q, want %q", lang, "Go")
+	}
+	if code == "" {
+		t.Error("empty code")
+	}
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"path/filepath"
 	"slices"
+	"time"
 
 	"rafaelmartins.com/p/website/internal/runner"
 )
@@ -19,14 +20,25 @@ func (p *Project) GetSkipIfExists() *string {
 		return nil
 	}
 
-	rv := filepath.Join(p.GetBaseDestination(), p.Repo, "index.html")
+	rv := filepath.Join(p.GetBaseDestination(), p.slug(), "index.html")
 	return &rv
 }
 
+// existing projects are revalidated after the ttl, if any.
+func (p *Project) GetSkipIfExistsTTL() time.Duration {
+	return p.TTL
+}
+
 func (p *Project) GetTasks() ([]*runner.Task, error) {
 	if err := p.init(); err != nil {
 		return nil, err
 	}
+	if err := p.updateState(); err != nil {
+		return nil, err
+	}
+	if err := p.updateOverview(); err != nil {
+		return nil, err
+	}
 
 	rv := []*runner.Task{}
 	files := []string{}
@@ -36,10 +48,58 @@ func (p *Project) GetTasks() ([]*runner.Task, error) {
 	}
 	files = append(files, p.Files...)
 
+	// only the pages showing the star count are checked
+	if p.starsOnly {
+		p.starsOnly = false
+		return append(rv, p.getBadgesTasks()...), nil
+	}
+
 	if len(p.CDocsHeaders) > 0 {
 		rv = append(rv, runner.NewTask(p, &cDocs{proj: p}))
 	}
 
+	if p.GoImport != "" && len(p.GoPackages) > 0 {
+		tasks, err := p.getGoImportTasks()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, tasks...)
+	}
+
+	if p.godocsUrl != "" {
+		tasks, err := p.getGoDocsTasks()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, tasks...)
+	}
+
+	if p.ReleasesPerPage != 0 {
+		rv = append(rv, p.getReleasesTasks()...)
+	}
+
+	if p.badgesUrl != "" {
+		rv = append(rv, p.getBadgesTasks()...)
+	}
+
+	if p.downloadsUrl != "" {
+		tasks, err := p.getDownloadsTasks()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, tasks...)
+	}
+
+	if p.issuesUrl != "" {
+		rv = append(rv, runner.NewTask(p, &issues{proj: p}))
+	}
+
+	if p.sourceUrl != "" {
+		tasks, sfiles := p.getSourceTasks()
+		rv = append(rv, tasks...)
+		files = append(files, sfiles...)
+	}
+
 	if p.DfuReleaseAssetsPattern != "" {
 		rv = append(rv, runner.NewTask(p, &dfu{proj: p}))
 	}
@@ -79,12 +79,76 @@ func (t *Task) generator() (Generator, error) {
 	return t.gen, nil
 }
 
+// outputs tracks the destinations of the tasks queued in a run, so that tasks
+// depending on files generated by other tasks of the same or previous task
+// groups wait for them to be done.
+type outputs struct {
+	m    sync.Mutex
+	done map[string]chan struct{}
+}
+
+func (o *outputs) add(dest string) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.done == nil {
+		o.done = map[string]chan struct{}{}
+	}
+	if _, found := o.done[dest]; !found {
+		o.done[dest] = make(chan struct{})
+	}
+}
+
+func (o *outputs) get(dest string) chan struct{} {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.done[filepath.Clean(dest)]
+}
+
+func (o *outputs) finish(dest string) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if ch, found := o.done[dest]; found {
+		close(ch)
+		delete(o.done, dest)
+	}
+}
+
+func (t *Task) wait(ctx context.Context, basedir string, out *outputs) error {
+	gen, err := t.generator()
+	if err != nil {
+		return err
+	}
+
+	paths, err := gen.GetPaths()
+	if err != nil {
+		return err
+	}
+
+	dest := t.destination(basedir)
+	for _, p := range paths {
+		if filepath.Clean(p) == dest {
+			continue
+		}
+		if ch := out.get(p); ch != nil {
+			select {
+			case <-ch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
 type tst struct {
 	t time.Time
 	e bool
 }
 
-func (t *Task) outdated(basedir string, cfg Config, force bool) (bool, bool, error) {
+func (t *Task) outdated(basedir string, cfg Config, force bool, out *outputs) (bool, bool, error) {
 	if force {
 		return true, false, nil
 	}
@@ -122,6 +186,10 @@ func (t *Task) outdated(basedir string, cfg Config, force bool) (bool, bool, err
 	for _, p := range slices.Compact(paths) {
 		st, err := os.Stat(p)
 		if err != nil {
+			// files to be generated by other tasks of this run
+			if errors.Is(err, fs.ErrNotExist) && out.get(p) != nil {
+				return true, false, nil
+			}
 			return false, false, err
 		}
 
@@ -225,6 +293,56 @@ func NewTaskGroup(impl TaskGroupImpl) *TaskGroup {
 	}
 }
 
+// revalidate checks if the task group can be skipped because its outputs
+// already exist. groups with a ttl are revalidated once their outputs get
+// stale, and their tasks are checked again if anything changed.
+func (t *TaskGroup) revalidate(basedir string, force bool) (bool, error) {
+	if force {
+		return false, nil
+	}
+
+	implf, ok := t.impl.(interface{ GetSkipIfExists() *string })
+	if !ok {
+		return false, nil
+	}
+
+	skip := implf.GetSkipIfExists()
+	if skip == nil {
+		return false, nil
+	}
+
+	fn := path.Join(basedir, *skip)
+	st, err := os.Stat(fn)
+	if err != nil {
+		return false, nil
+	}
+
+	implt, ok := t.impl.(interface {
+		GetSkipIfExistsTTL() time.Duration
+		Revalidate() (bool, error)
+	})
+	if !ok {
+		return true, nil
+	}
+
+	ttl := implt.GetSkipIfExistsTTL()
+	if ttl <= 0 || time.Since(st.ModTime()) < ttl {
+		return true, nil
+	}
+
+	changed, err := implt.Revalidate()
+	if err != nil {
+		return false, err
+	}
+
+	// restart the ttl
+	now := time.Now()
+	if err := os.Chtimes(fn, now, now); err != nil {
+		return false, err
+	}
+	return !changed, nil
+}
+
 type Config interface {
 	GetTimeStamp() (time.Time, error)
 }
@@ -241,6 +359,7 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 	}()
 
 	queue := make(chan *taskJob, 100)
+	out := &outputs{}
 
 	go func() {
 		defer close(queue)
@@ -251,12 +370,15 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 				continue
 			}
 
-			if implf, ok := group.impl.(interface{ GetSkipIfExists() *string }); ok && !force {
-				if skip := implf.GetSkipIfExists(); skip != nil {
-					if _, err := os.Stat(path.Join(basedir, *skip)); err == nil {
-						continue
-					}
+			skip, err := group.revalidate(basedir, force)
+			if err != nil {
+				queue <- &taskJob{
+					err: err,
 				}
+				return
+			}
+			if skip {
+				continue
 			}
 
 			tasks, err := group.impl.GetTasks()
@@ -267,9 +389,15 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 				return
 			}
 
+			// all the destinations of the group are registered before
+			// queueing, as tasks may depend on any of them
+			for _, task := range tasks {
+				out.add(task.destination(basedir))
+			}
+
 			for _, task := range tasks {
 				if mayReload {
-					outd, isExe, err := task.outdated(basedir, cfg, force)
+					outd, isExe, err := task.outdated(basedir, cfg, force, out)
 					if err != nil {
 						queue <- &taskJob{
 							err: err,
@@ -277,6 +405,7 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 						return
 					}
 					if !outd {
+						out.finish(task.destination(basedir))
 						continue
 					}
 
@@ -307,9 +436,11 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 		}
 	}()
 
-	ctx := context.Background()
-	nworkers := int64(runtime.NumCPU())
-	sem := semaphore.NewWeighted(nworkers)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sem := semaphore.NewWeighted(int64(runtime.NumCPU()))
+	wg := sync.WaitGroup{}
 	failures := atomic.Int32{}
 	outdated := atomic.Int32{}
 
@@ -318,15 +449,26 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 			return job.err
 		}
 
-		if err := sem.Acquire(ctx, 1); err != nil {
-			return err
-		}
-
+		wg.Add(1)
 		go func(task *Task) {
+			defer wg.Done()
+			defer out.finish(task.destination(basedir))
+
+			// tasks waiting for their dependencies must not hold a worker
+			if err := task.wait(ctx, basedir, out); err != nil {
+				failures.Add(1)
+				log.Printf("  %-8s  %s: %s", "[ERROR]", task.destination(basedir), err)
+				return
+			}
+
+			if err := sem.Acquire(ctx, 1); err != nil {
+				failures.Add(1)
+				return
+			}
 			defer sem.Release(1)
 
 			if !job.outdated {
-				outd, _, err := task.outdated(basedir, cfg, force)
+				outd, _, err := task.outdated(basedir, cfg, force, out)
 				if err != nil {
 					failures.Add(1)
 					log.Printf("  %-8s  %s: %s", "[ERROR]", task.destination(basedir), err)
@@ -346,9 +488,7 @@ func Run(groups []*TaskGroup, basedir string, cfg Config, runserver bool, force
 		}(job.task)
 	}
 
-	if err := sem.Acquire(ctx, nworkers); err != nil {
-		return err
-	}
+	wg.Wait()
 
 	if f := failures.Load(); f > 0 {
 		return fmt.Errorf("runner: %d tasks failed", f)
@@ -0,0 +1,377 @@
+package sanitizer
+
+import (
+	"html"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	phtml "github.com/tdewolff/parse/v2/html"
+)
+
+type Policy struct {
+	// tags allowed, with their allowed attributes
+	Tags map[string][]string
+
+	// attributes allowed for all the tags. attributes ending with "*" are
+	// matched by prefix.
+	Attributes []string
+
+	// attributes that hold urls, that must use one of the allowed schemes
+	URLAttributes []string
+	Schemes       []string
+
+	// tags that are stripped along with their content
+	StripContent []string
+
+	// css properties allowed in style attributes. declarations of any other
+	// property are removed.
+	StyleProperties []string
+
+	// prefix added to the element names and to the references to them, so
+	// that the content can't clobber the elements used by the page scripts.
+	IDPrefix        string
+	IDAttributes    []string
+	IDRefAttributes []string
+}
+
+var Default = &Policy{
+	Tags: map[string][]string{
+		"a":          {"href", "name", "target", "rel"},
+		"abbr":       nil,
+		"b":          nil,
+		"blockquote": {"cite"},
+		"br":         nil,
+		"caption":    nil,
+		"cite":       nil,
+		"code":       nil,
+		"col":        {"span", "align"},
+		"colgroup":   {"span", "align"},
+		"dd":         nil,
+		"del":        {"cite", "datetime"},
+		"details":    {"open"},
+		"dfn":        nil,
+		"div":        {"align"},
+		"dl":         nil,
+		"dt":         nil,
+		"em":         nil,
+		"figcaption": nil,
+		"figure":     nil,
+		"h1":         {"align"},
+		"h2":         {"align"},
+		"h3":         {"align"},
+		"h4":         {"align"},
+		"h5":         {"align"},
+		"h6":         {"align"},
+		"hr":         nil,
+		"i":          nil,
+		"img":        {"src", "srcset", "alt", "width", "height", "align", "loading"},
+		"input":      {"type", "checked", "disabled"},
+		"ins":        {"cite", "datetime"},
+		"kbd":        nil,
+		"li":         {"value"},
+		"mark":       nil,
+		"ol":         {"start", "type", "reversed"},
+		"p":          {"align"},
+		"picture":    nil,
+		"pre":        {"tabindex"},
+		"q":          {"cite"},
+		"rp":         nil,
+		"rt":         nil,
+		"ruby":       nil,
+		"s":          nil,
+		"samp":       nil,
+		"section":    nil,
+		"small":      nil,
+		"source":     {"srcset", "media", "type", "sizes"},
+		"span":       nil,
+		"strike":     nil,
+		"strong":     nil,
+		"sub":        nil,
+		"summary":    nil,
+		"sup":        nil,
+		"table":      nil,
+		"tbody":      nil,
+		"td":         {"colspan", "rowspan", "align"},
+		"tfoot":      nil,
+		"th":         {"colspan", "rowspan", "align", "scope"},
+		"thead":      nil,
+		"time":       {"datetime"},
+		"tr":         nil,
+		"tt":         nil,
+		"u":          nil,
+		"ul":         nil,
+		"var":        nil,
+	},
+	Attributes:    []string{"id", "class", "title", "lang", "dir", "role", "style", "aria-*"},
+	URLAttributes: []string{"href", "src", "srcset", "cite"},
+	Schemes:       []string{"http", "https", "mailto"},
+	StripContent: []string{
+		"script", "style", "iframe", "object", "embed", "template", "noscript",
+		"noembed", "noframes", "textarea", "title", "xmp", "plaintext",
+	},
+	StyleProperties: []string{
+		"color", "background-color", "font-weight", "font-style",
+		"text-decoration", "text-align", "vertical-align", "white-space",
+		"display", "margin-right", "padding", "border",
+		"border-spacing", "user-select", "-webkit-user-select",
+		"-webkit-text-size-adjust",
+	},
+	IDPrefix:     "user-content-",
+	IDAttributes: []string{"id", "name"},
+	IDRefAttributes: []string{
+		"aria-labelledby", "aria-describedby", "aria-controls", "aria-owns",
+	},
+}
+
+// void tags never get an end tag, their content can't be stripped
+var voidTags = []string{
+	"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta",
+	"source", "track", "wbr",
+}
+
+func (p *Policy) allowedAttr(tag string, attr string) bool {
+	if slices.Contains(p.Tags[tag], attr) {
+		return true
+	}
+	for _, a := range p.Attributes {
+		if prefix, found := strings.CutSuffix(a, "*"); found {
+			if strings.HasPrefix(attr, prefix) {
+				return true
+			}
+			continue
+		}
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) allowedUrl(u string) bool {
+	// browsers ignore whitespace and control characters inside the scheme
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+
+	idx := strings.IndexAny(u, ":/?#")
+	if idx == -1 || u[idx] != ':' {
+		return true
+	}
+	return slices.Contains(p.Schemes, strings.ToLower(u[:idx]))
+}
+
+func (p *Policy) allowedValue(attr string, value string) bool {
+	switch {
+	case attr == "srcset":
+		for candidate := range strings.SplitSeq(value, ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 && !p.allowedUrl(fields[0]) {
+				return false
+			}
+		}
+		return true
+
+	case slices.Contains(p.URLAttributes, attr):
+		return p.allowedUrl(value)
+
+	case attr == "style":
+		v := strings.ToLower(strings.Join(strings.Fields(value), ""))
+		for _, bad := range []string{"url(", "expression(", "javascript:", "@import", "behavior:", "\\", "/*"} {
+			if strings.Contains(v, bad) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterStyle removes the declarations of the css properties that are not
+// allowed, returning whether any declaration was removed.
+func (p *Policy) filterStyle(value string) (string, bool) {
+	decls := []string{}
+	removed := false
+	for decl := range strings.SplitSeq(value, ";") {
+		decl = strings.TrimSpace(decl)
+		if decl == "" {
+			continue
+		}
+		prop, _, found := strings.Cut(decl, ":")
+		if !found || !slices.Contains(p.StyleProperties, strings.ToLower(strings.TrimSpace(prop))) {
+			removed = true
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	if !removed {
+		return value, false
+	}
+	return strings.Join(decls, "; "), true
+}
+
+func (p *Policy) prefixID(id string) string {
+	if id == "" || strings.HasPrefix(id, p.IDPrefix) {
+		return id
+	}
+	return p.IDPrefix + id
+}
+
+// prefixValue namespaces the element names, and the fragments and attributes
+// referencing them.
+func (p *Policy) prefixValue(attr string, value string) string {
+	if p.IDPrefix == "" {
+		return value
+	}
+
+	switch {
+	case slices.Contains(p.IDAttributes, attr):
+		return p.prefixID(value)
+
+	case slices.Contains(p.IDRefAttributes, attr):
+		ids := strings.Fields(value)
+		for i, id := range ids {
+			ids[i] = p.prefixID(id)
+		}
+		return strings.Join(ids, " ")
+
+	case attr == "href":
+		if frag, found := strings.CutPrefix(value, "#"); found && frag != "" {
+			return "#" + p.prefixID(frag)
+		}
+	}
+	return value
+}
+
+func attrValue(raw []byte) string {
+	v := string(raw)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		v = v[1 : len(v)-1]
+	}
+	return html.UnescapeString(v)
+}
+
+func (p *Policy) sanitize(src string) (string, []string) {
+	rv := strings.Builder{}
+	stripped := []string{}
+
+	// start tags are only emitted when terminated, otherwise they would
+	// swallow the markup following the content
+	start := strings.Builder{}
+	strip := func(s string) {
+		if !slices.Contains(stripped, s) {
+			stripped = append(stripped, s)
+		}
+	}
+
+	var (
+		tag        string
+		tagAllowed bool
+		stripTag   string
+		stripDepth int
+	)
+
+	l := phtml.NewLexer(parse.NewInputString(src))
+	for {
+		tt, data := l.Next()
+
+		if stripDepth > 0 {
+			switch tt {
+			case phtml.ErrorToken:
+				return rv.String(), stripped
+			case phtml.StartTagToken:
+				if string(l.Text()) == stripTag {
+					stripDepth++
+				}
+			case phtml.EndTagToken:
+				if string(l.Text()) == stripTag {
+					stripDepth--
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case phtml.ErrorToken:
+			if start.Len() > 0 {
+				strip("unterminated <" + tag + ">")
+			}
+			return rv.String(), stripped
+
+		case phtml.TextToken:
+			rv.Write(data)
+
+		case phtml.StartTagToken:
+			tag = strings.ToLower(string(l.Text()))
+			_, tagAllowed = p.Tags[tag]
+			if tagAllowed {
+				start.Reset()
+				start.WriteString("<" + tag)
+				continue
+			}
+
+			strip("<" + tag + ">")
+			if slices.Contains(p.StripContent, tag) && !slices.Contains(voidTags, tag) {
+				stripTag = tag
+				stripDepth = 1
+			}
+
+		case phtml.AttributeToken:
+			if !tagAllowed {
+				continue
+			}
+
+			attr := strings.ToLower(string(l.AttrKey()))
+			value := attrValue(l.AttrVal())
+			if !p.allowedAttr(tag, attr) || !p.allowedValue(attr, value) {
+				strip(tag + "[" + attr + "]")
+				continue
+			}
+			if attr == "style" {
+				removed := false
+				value, removed = p.filterStyle(value)
+				if removed {
+					strip(tag + "[" + attr + "]")
+				}
+				if value == "" {
+					continue
+				}
+			}
+			value = p.prefixValue(attr, value)
+			start.WriteString(" " + attr + "=\"" + html.EscapeString(value) + "\"")
+
+		case phtml.StartTagCloseToken, phtml.StartTagVoidToken:
+			if tagAllowed {
+				rv.WriteString(start.String())
+				rv.Write(data)
+				start.Reset()
+			}
+
+		case phtml.EndTagToken:
+			t := strings.ToLower(string(l.Text()))
+			if _, found := p.Tags[t]; found {
+				rv.WriteString("</" + t + ">")
+			}
+
+		case phtml.CommentToken:
+			// comments are common in readmes and harmless to drop, not worth
+			// reporting.
+
+		default:
+			strip(strings.ToLower(tt.String()))
+		}
+	}
+}
+
+// Sanitize removes everything not allowed by the policy from the html
+// source, logging what was stripped. the name identifies the source in the
+// logs.
+func (p *Policy) Sanitize(name string, src string) string {
+	rv, stripped := p.sanitize(src)
+	if len(stripped) > 0 {
+		log.Printf("sanitizer: %s: stripped %s", name, strings.Join(stripped, ", "))
+	}
+	return rv
+}
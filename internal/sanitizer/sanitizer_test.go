@@ -0,0 +1,155 @@
+package sanitizer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		src      string
+		want     string
+		stripped []string
+	}{
+		{
+			"allowed",
+			`<p class="foo" id=bar>hello <b>world</b> &amp; <a href="https://example.com/" title='x'>link</a></p>`,
+			`<p class="foo" id="user-content-bar">hello <b>world</b> &amp; <a href="https://example.com/" title="x">link</a></p>`,
+			nil,
+		},
+		{
+			"relative urls",
+			`<a href="../foo.html#bar">a</a><img src="img/a.png" alt="a"><a href="?x=1">b</a>`,
+			`<a href="../foo.html#bar">a</a><img src="img/a.png" alt="a"><a href="?x=1">b</a>`,
+			nil,
+		},
+		{
+			"script",
+			`<p>a</p><script>alert("</p>")</script><ScRiPt src="x.js"></ScRiPt><p>b</p>`,
+			`<p>a</p><p>b</p>`,
+			[]string{"<script>"},
+		},
+		{
+			"event handlers",
+			`<img src="a.png" onerror="alert(1)"><p ONCLICK='x()'>a</p>`,
+			`<img src="a.png"><p>a</p>`,
+			[]string{"img[onerror]", "p[onclick]"},
+		},
+		{
+			"javascript urls",
+			`<a href="javascript:alert(1)">a</a><a href=" JaVa&#x09;ScRiPt:alert(1)">b</a><a href="data:text/html,x">c</a>`,
+			`<a>a</a><a>b</a><a>c</a>`,
+			[]string{"a[href]"},
+		},
+		{
+			"srcset",
+			`<source srcset="a.png 1x, javascript:x 2x"><source srcset="a.png 1x, https://example.com/b.png 2x">`,
+			`<source><source srcset="a.png 1x, https://example.com/b.png 2x">`,
+			[]string{"source[srcset]"},
+		},
+		{
+			"style",
+			`<span style="color: #fff">a</span><span style="background: URL (x)">b</span><span style="width: expression(alert(1))">c</span>`,
+			`<span style="color: #fff">a</span><span>b</span><span>c</span>`,
+			[]string{"span[style]"},
+		},
+		{
+			"style properties",
+			`<div style="position:fixed;top:0;left:0;width:100%;height:100%">a</div><td style="text-align:left;">b</td><span style="color: red; position: absolute">c</span><span style="color:red/**/">d</span>`,
+			`<div>a</div><td style="text-align:left;">b</td><span style="color: red">c</span><span>d</span>`,
+			[]string{"div[style]", "span[style]"},
+		},
+		{
+			"ids",
+			`<h1 id="title">a</h1><a name="x"></a><a href="#title">b</a><a href="#user-content-x">c</a><a href="#">d</a><p id="user-content-y" aria-labelledby="title  x">e</p>`,
+			`<h1 id="user-content-title">a</h1><a name="user-content-x"></a><a href="#user-content-title">b</a><a href="#user-content-x">c</a><a href="#">d</a><p id="user-content-y" aria-labelledby="user-content-title user-content-x">e</p>`,
+			nil,
+		},
+		{
+			"unterminated tag",
+			`<p>a</p><img src=x onerror=alert(1)//`,
+			`<p>a</p>`,
+			[]string{"img[onerror]", "unterminated <img>"},
+		},
+		{
+			"unknown tags keep content",
+			`<center><blink>a</blink></center><form action="x"><p>b</p></form>`,
+			`a<p>b</p>`,
+			[]string{"<center>", "<blink>", "<form>"},
+		},
+		{
+			"nested stripped content",
+			`<object><object><p>a</p></object><p>b</p></object><p>c</p>`,
+			`<p>c</p>`,
+			[]string{"<object>"},
+		},
+		{
+			"void tags",
+			`<embed src="x.swf"><p>a</p><meta http-equiv="refresh" content="0;url=x"><br/>`,
+			`<p>a</p><br/>`,
+			[]string{"<embed>", "<meta>"},
+		},
+		{
+			"iframe and style",
+			`<iframe src="https://example.com"></iframe><style>body{display:none}</style><p>a</p>`,
+			`<p>a</p>`,
+			[]string{"<iframe>", "<style>"},
+		},
+		{
+			"svg and comments",
+			`<!-- foo --><svg onload="alert(1)"><script>x</script></svg><p>a</p>`,
+			`<p>a</p>`,
+			[]string{"svg"},
+		},
+		{
+			"attribute escaping",
+			`<img alt="a &quot;b&quot; <c>" src=x.png>`,
+			`<img alt="a &#34;b&#34; &lt;c&gt;" src="x.png">`,
+			nil,
+		},
+		{
+			"aria",
+			`<div role="note" aria-label="x" data-foo="bar">a</div>`,
+			`<div role="note" aria-label="x">a</div>`,
+			[]string{"div[data-foo]"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stripped := Default.sanitize(tt.src)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if tt.stripped == nil {
+				tt.stripped = []string{}
+			}
+			if !slices.Equal(stripped, tt.stripped) {
+				t.Errorf("got stripped %q, want %q", stripped, tt.stripped)
+			}
+		})
+	}
+}
+
+func TestAllowedUrl(t *testing.T) {
+	for _, tt := range []struct {
+		url     string
+		allowed bool
+	}{
+		{"", true},
+		{"foo.html", true},
+		{"/foo/bar", true},
+		{"#foo", true},
+		{"foo/bar:baz", true},
+		{"http://example.com", true},
+		{"HTTPS://example.com", true},
+		{"mailto:foo@example.com", true},
+		{"javascript:alert(1)", false},
+		{"java\nscript:alert(1)", false},
+		{"vbscript:x", false},
+		{"data:image/png;base64,xxx", false},
+	} {
+		if got := Default.allowedUrl(tt.url); got != tt.allowed {
+			t.Errorf("%q: got %t, want %t", tt.url, got, tt.allowed)
+		}
+	}
+}
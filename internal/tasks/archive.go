@@ -0,0 +1,249 @@
+package tasks
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"time"
+
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type archiveMonth struct {
+	month time.Month
+	posts []*paginationPost
+}
+
+type archiveYear struct {
+	year   int
+	count  int
+	months []*archiveMonth
+}
+
+type archiveTaskImpl struct {
+	baseDestination string
+	title           string
+	description     string
+	sources         []*generators.ContentSource
+	slug            string
+	year            int
+	month           time.Month
+	years           []*archiveYear
+	template        string
+	templateCtx     map[string]any
+	layoutCtx       *templates.LayoutContext
+	deps            []string
+
+	openGraph                    *opengraph.Config
+	openGraphImageGen            *opengraph.OpenGraphImageGen
+	openGraphPregeneratedBaseUrl string
+}
+
+func archiveURL(baseDestination string, year int, month time.Month) string {
+	rv := path.Join("/", baseDestination, "archive")
+	if year > 0 {
+		rv = path.Join(rv, fmt.Sprintf("%04d", year))
+	}
+	if month > 0 {
+		rv = path.Join(rv, fmt.Sprintf("%02d", month))
+	}
+	return rv + "/"
+}
+
+func (t *archiveTaskImpl) GetDestination() string {
+	return filepath.Join(filepath.FromSlash(t.slug), "index.html")
+}
+
+// archive returns a new archive context for each generator, as the entries
+// are grouped in it when rendering.
+func (t *archiveTaskImpl) archive() *templates.ContentArchive {
+	rv := &templates.ContentArchive{
+		URL:   archiveURL(t.baseDestination, 0, 0),
+		Year:  t.year,
+		Month: t.month,
+	}
+	for _, y := range t.years {
+		year := &templates.ContentArchiveYear{
+			Year:  y.year,
+			URL:   archiveURL(t.baseDestination, y.year, 0),
+			Count: y.count,
+		}
+		for _, m := range y.months {
+			year.Months = append(year.Months, &templates.ContentArchiveMonth{
+				Month: m.month,
+				URL:   archiveURL(t.baseDestination, y.year, m.month),
+				Count: len(m.posts),
+			})
+		}
+		rv.Years = append(rv.Years, year)
+	}
+	return rv
+}
+
+func (t *archiveTaskImpl) GetGenerator() (runner.Generator, error) {
+	return &generators.Content{
+		Title:             t.title,
+		Description:       t.description,
+		URL:               archiveURL(t.baseDestination, t.year, t.month),
+		Slug:              t.slug,
+		Toc:               false,
+		Search:            new(false),
+		Sources:           t.sources,
+		IsPost:            true,
+		MetadataOnly:      true,
+		ExtraDependencies: t.deps,
+		Template:          t.template,
+		TemplateCtx:       t.templateCtx,
+		Pagination: &templates.ContentPagination{
+			AtomURL: path.Join("/", t.baseDestination, "atom.xml"),
+		},
+		Archive:                      t.archive(),
+		LayoutCtx:                    t.layoutCtx,
+		OpenGraph:                    t.openGraph,
+		OpenGraphImageGen:            t.openGraphImageGen,
+		OpenGraphPregeneratedBaseUrl: t.openGraphPregeneratedBaseUrl,
+	}, nil
+}
+
+// Archive generates archive pages for posts, grouped by year and month of
+// publication. posts without publication date are not archived.
+type Archive struct {
+	Title           string
+	Description     string
+	SourceDirs      []*PostsSources
+	SortReverse     bool
+	BaseDestination string
+	Template        string
+	TemplateCtx     map[string]any
+	WithSidebar     bool
+	StateDirectory  string
+
+	OpenGraph         *opengraph.Config
+	OpenGraphImageGen *opengraph.OpenGraphImageGen
+}
+
+func (a *Archive) GetBaseDestination() string {
+	return a.BaseDestination
+}
+
+func (a *Archive) GetTasks() ([]*runner.Task, error) {
+	tmpl := a.Template
+	if tmpl == "" {
+		tmpl = "archive.html"
+	}
+
+	posts, err := listPaginationPosts(a.SourceDirs, a.SortReverse)
+	if err != nil {
+		return nil, err
+	}
+
+	// posts are sorted, so are years and months
+	years := []*archiveYear{}
+	for _, post := range posts {
+		if post.Published.IsZero() {
+			continue
+		}
+
+		var y *archiveYear
+		if l := len(years); l > 0 && years[l-1].year == post.Published.Year() {
+			y = years[l-1]
+		} else {
+			y = &archiveYear{year: post.Published.Year()}
+			years = append(years, y)
+		}
+		y.count++
+
+		var m *archiveMonth
+		if l := len(y.months); l > 0 && y.months[l-1].month == post.Published.Month() {
+			m = y.months[l-1]
+		} else {
+			m = &archiveMonth{month: post.Published.Month()}
+			y.months = append(y.months, m)
+		}
+		m.posts = append(m.posts, post)
+	}
+
+	// all the pages list the years and months, their index is tracked as a
+	// dependency instead of the sources of all the archived posts
+	index := map[string]int{}
+	for _, y := range years {
+		for _, m := range y.months {
+			index[fmt.Sprintf("%04d-%02d", y.year, m.month)] = len(m.posts)
+		}
+	}
+	dep, err := writeState(filepath.Join(a.StateDirectory, "archive", a.BaseDestination, "index.json"), index)
+	if err != nil {
+		return nil, err
+	}
+	deps := []string{dep}
+
+	title := "Archive"
+	if a.Title != "" {
+		title = a.Title + " archive"
+	}
+
+	layoutCtx := &templates.LayoutContext{
+		WithSidebar: a.WithSidebar,
+	}
+
+	// the opengraph image of the main archive page is reused by the others
+	ogBaseUrl := ""
+	task := func(slug string, year int, month time.Month, title string, posts []*paginationPost) *runner.Task {
+		srcs := []*generators.ContentSource{}
+		for _, post := range posts {
+			srcs = append(srcs, post.Source)
+		}
+
+		return runner.NewTask(a,
+			&archiveTaskImpl{
+				baseDestination:              a.BaseDestination,
+				title:                        title,
+				description:                  a.Description,
+				sources:                      srcs,
+				slug:                         slug,
+				year:                         year,
+				month:                        month,
+				years:                        years,
+				template:                     tmpl,
+				templateCtx:                  a.TemplateCtx,
+				layoutCtx:                    layoutCtx,
+				deps:                         deps,
+				openGraph:                    a.OpenGraph,
+				openGraphImageGen:            a.OpenGraphImageGen,
+				openGraphPregeneratedBaseUrl: ogBaseUrl,
+			},
+		)
+	}
+
+	all := []*paginationPost{}
+	for _, y := range years {
+		for _, m := range y.months {
+			all = append(all, m.posts...)
+		}
+	}
+
+	rv := []*runner.Task{
+		task("archive", 0, 0, title, all),
+	}
+	ogBaseUrl = archiveURL(a.BaseDestination, 0, 0)
+
+	for _, y := range years {
+		ys := fmt.Sprintf("%04d", y.year)
+
+		yposts := []*paginationPost{}
+		for _, m := range y.months {
+			yposts = append(yposts, m.posts...)
+			rv = append(rv,
+				task(path.Join("archive", ys, fmt.Sprintf("%02d", m.month)), y.year, m.month,
+					fmt.Sprintf("%s: %s %d", title, m.month, y.year), m.posts),
+			)
+		}
+		rv = append(rv,
+			task(path.Join("archive", ys), y.year, 0, fmt.Sprintf("%s: %d", title, y.year), yposts),
+		)
+	}
+	return rv, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"rafaelmartins.com/p/website/internal/generators"
 	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/theme"
 )
 
 type embedTaskImpl struct {
@@ -55,7 +56,8 @@ func (e *Embed) GetTasks() ([]*runner.Task, error) {
 
 	rv := []*runner.Task{}
 	for _, ee := range entries {
-		if !ee.IsDir() {
+		// assets overridden by themes are handled by ThemeAssets
+		if !ee.IsDir() && theme.Lookup(theme.Assets, ee.Name()) == "" {
 			rv = append(rv, runner.NewTask(e, &embedTaskImpl{
 				fs:   e.FS,
 				name: filepath.Join(dir, ee.Name()),
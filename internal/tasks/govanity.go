@@ -0,0 +1,202 @@
+package tasks
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/config"
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type goImportTask struct {
+	importPath string
+	url        string
+	template   string
+	entry      *templates.GoImportContentEntry
+}
+
+func (t *goImportTask) GetDestination() string {
+	return filepath.Join(filepath.FromSlash(strings.Trim(t.url, "/")), "index.html")
+}
+
+func (t *goImportTask) GetGenerator() (runner.Generator, error) {
+	return &generators.GoImport{
+		Title:    t.importPath,
+		URL:      t.url,
+		Template: t.template,
+		Entry:    t.entry,
+	}, nil
+}
+
+type goVanityIndexTask struct {
+	baseDestination string
+	title           string
+	description     string
+	template        string
+	entries         []*templates.ContentEntry
+	layoutCtx       *templates.LayoutContext
+
+	openGraph         *opengraph.Config
+	openGraphImageGen *opengraph.OpenGraphImageGen
+}
+
+func (t *goVanityIndexTask) GetDestination() string {
+	return filepath.Join(filepath.FromSlash(t.baseDestination), "index.html")
+}
+
+func (t *goVanityIndexTask) GetGenerator() (runner.Generator, error) {
+	url := path.Join("/", t.baseDestination)
+	if url != "/" {
+		url += "/"
+	}
+
+	return &generators.GoVanityIndex{
+		Title:       t.title,
+		Description: t.description,
+		URL:         url,
+		Template:    t.template,
+		Entries:     t.entries,
+		LayoutCtx:   t.layoutCtx,
+
+		OpenGraph:         t.openGraph,
+		OpenGraphImageGen: t.openGraphImageGen,
+	}, nil
+}
+
+type GoVanity struct {
+	Title           string
+	Description     string
+	SiteURL         string
+	BaseDestination string
+	Template        string
+	TemplateImport  string
+	WithSidebar     bool
+	Modules         []*config.GoVanityModule
+
+	OpenGraph         *opengraph.Config
+	OpenGraphImageGen *opengraph.OpenGraphImageGen
+}
+
+func (*GoVanity) GetBaseDestination() string {
+	// module pages are placed according to their import paths
+	return ""
+}
+
+func (g *GoVanity) getIndexDestination() string {
+	if g.BaseDestination == "" {
+		return "go"
+	}
+	return g.BaseDestination
+}
+
+func (g *GoVanity) GetTasks() ([]*runner.Task, error) {
+	tmpl := g.Template
+	if tmpl == "" {
+		tmpl = "go-vanity.html"
+	}
+
+	tmplImport := g.TemplateImport
+	if tmplImport == "" {
+		tmplImport = "go-import.html"
+	}
+
+	u, err := url.Parse(g.SiteURL)
+	if err != nil {
+		return nil, err
+	}
+	host := strings.TrimSuffix(path.Join(u.Host, u.Path), "/")
+
+	title := g.Title
+	if title == "" {
+		title = "Go Modules"
+	}
+
+	description := g.Description
+	if description == "" {
+		description = fmt.Sprintf("Go modules available from %s", host)
+	}
+
+	rv := []*runner.Task{}
+	entries := []*templates.ContentEntry{}
+	for _, mod := range g.Modules {
+		if mod == nil {
+			continue
+		}
+		if mod.Import == "" || mod.Repo == "" {
+			return nil, fmt.Errorf("go-vanity: module requires import and repo: %q", mod.Import)
+		}
+
+		p, found := strings.CutPrefix(mod.Import, host+"/")
+		if !found {
+			return nil, fmt.Errorf("go-vanity: import path is not served by %s: %s", host, mod.Import)
+		}
+
+		src, srcDir, srcFile := mod.GetSource()
+		paths := []string{""}
+		for _, pkg := range mod.Packages {
+			v := path.Clean(strings.Trim(pkg, "/"))
+			if v == "." || v == ".." || strings.HasPrefix(v, "../") {
+				return nil, fmt.Errorf("go-vanity: invalid package path: %s", pkg)
+			}
+			paths = append(paths, v)
+		}
+
+		var entry *templates.GoImportContentEntry
+		for _, pkg := range paths {
+			importPath := path.Join(mod.Import, pkg)
+			redirect := mod.Redirect
+			if redirect == "" {
+				redirect = "https://pkg.go.dev/" + importPath
+			}
+
+			e := &templates.GoImportContentEntry{
+				Prefix:        mod.Import,
+				VCS:           mod.GetVCS(),
+				RepoURL:       mod.Repo,
+				SourceURL:     src,
+				SourceDirURL:  srcDir,
+				SourceFileURL: srcFile,
+				RedirectURL:   redirect,
+			}
+			if pkg == "" {
+				entry = e
+			} else {
+				entry.Packages = append(entry.Packages, importPath)
+			}
+
+			rv = append(rv, runner.NewTask(g, &goImportTask{
+				importPath: importPath,
+				url:        path.Join("/", p, pkg) + "/",
+				template:   tmplImport,
+				entry:      e,
+			}))
+		}
+
+		entries = append(entries, &templates.ContentEntry{
+			URL:      path.Join("/", p) + "/",
+			Title:    mod.Import,
+			GoImport: entry,
+		})
+	}
+
+	rv = append(rv, runner.NewTask(g, &goVanityIndexTask{
+		baseDestination: g.getIndexDestination(),
+		title:           title,
+		description:     description,
+		template:        tmpl,
+		entries:         entries,
+		layoutCtx: &templates.LayoutContext{
+			WithSidebar: g.WithSidebar,
+		},
+
+		openGraph:         g.OpenGraph,
+		openGraphImageGen: g.OpenGraphImageGen,
+	}))
+	return rv, nil
+}
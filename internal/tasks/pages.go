@@ -1,9 +1,16 @@
 package tasks
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"rafaelmartins.com/p/website/internal/content"
 	"rafaelmartins.com/p/website/internal/generators"
 	"rafaelmartins.com/p/website/internal/opengraph"
 	"rafaelmartins.com/p/website/internal/runner"
@@ -24,6 +31,7 @@ type pageTaskImpl struct {
 	template          string
 	templateCtx       map[string]any
 	layoutCtx         *templates.LayoutContext
+	section           *templates.ContentSection
 
 	openGraph         *opengraph.Config
 	openGraphImageGen *opengraph.OpenGraphImageGen
@@ -37,13 +45,7 @@ func (t *pageTaskImpl) GetDestination() string {
 }
 
 func (t *pageTaskImpl) GetGenerator() (runner.Generator, error) {
-	url := path.Join("/", t.baseDestination, t.slug) + ".html"
-	if t.prettyURL {
-		url = path.Join("/", t.baseDestination, t.slug)
-		if url != "/" {
-			url += "/"
-		}
-	}
+	url := pageURL(t.baseDestination, t.slug, t.prettyURL)
 
 	return &generators.Content{
 		Title:       t.title,
@@ -63,6 +65,7 @@ func (t *pageTaskImpl) GetGenerator() (runner.Generator, error) {
 		Template:          t.template,
 		TemplateCtx:       t.templateCtx,
 		LayoutCtx:         t.layoutCtx,
+		Section:           t.section,
 
 		OpenGraph:         t.openGraph,
 		OpenGraphImageGen: t.openGraphImageGen,
@@ -82,12 +85,15 @@ type PageSource struct {
 
 type Pages struct {
 	Sources           []*PageSource
+	SourceDir         string
+	Toc               bool
 	ExtraDependencies []string
 	PrettyURL         bool
 	BaseDestination   string
 	Template          string
 	TemplateCtx       map[string]any
 	WithSidebar       bool
+	StateDirectory    string
 	OpenGraphImageGen *opengraph.OpenGraphImageGen
 }
 
@@ -95,12 +101,96 @@ func (p *Pages) GetBaseDestination() string {
 	return p.BaseDestination
 }
 
+// listSourceDir discovers the supported content files from the source
+// directory, recursively. slugs are derived from the relative paths, and
+// titles from the frontmatter, when rendering.
+func (p *Pages) listSourceDir() ([]*PageSource, error) {
+	rv := []*PageSource{}
+	if p.SourceDir == "" {
+		return rv, nil
+	}
+
+	if err := filepath.WalkDir(p.SourceDir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) && fpath == p.SourceDir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if fpath != p.SourceDir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// some content formats are directories (e.g. textbundle)
+		if !content.IsSupported(fpath) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.SourceDir, fpath)
+		if err != nil {
+			return err
+		}
+		slug := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		if p.PrettyURL && path.Base(slug) == "index" {
+			slug = strings.TrimSuffix(strings.TrimSuffix(slug, "index"), "/")
+		}
+
+		rv = append(rv, &PageSource{
+			Slug: slug,
+			File: fpath,
+			Toc:  p.Toc,
+		})
+
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (p *Pages) sources() ([]*PageSource, error) {
+	discovered, err := p.listSourceDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// explicit entries win
+	rv := slices.Clone(p.Sources)
+	for _, d := range discovered {
+		if !slices.ContainsFunc(p.Sources, func(s *PageSource) bool {
+			return s.Slug == d.Slug || filepath.Clean(s.File) == filepath.Clean(d.File)
+		}) {
+			rv = append(rv, d)
+		}
+	}
+
+	seen := map[string]string{}
+	for _, s := range rv {
+		if f, found := seen[s.Slug]; found {
+			return nil, fmt.Errorf("pages: duplicated slug %q: %s, %s", s.Slug, f, s.File)
+		}
+		seen[s.Slug] = s.File
+	}
+	return rv, nil
+}
+
 func (p *Pages) GetTasks() ([]*runner.Task, error) {
 	tmpl := p.Template
 	if tmpl == "" {
 		tmpl = "base.html"
 	}
 
+	srcs, err := p.sources()
+	if err != nil {
+		return nil, err
+	}
+
 	deps := []string{}
 	for _, dep := range p.ExtraDependencies {
 		gdeps, err := filepath.Glob(dep)
@@ -110,31 +200,42 @@ func (p *Pages) GetTasks() ([]*runner.Task, error) {
 		deps = append(deps, gdeps...)
 	}
 
+	nodes, roots, err := p.tree(srcs)
+	if err != nil {
+		return nil, err
+	}
+
 	rv := []*runner.Task{}
-	for _, v := range p.Sources {
-		rv = append(rv,
-			runner.NewTask(p,
-				&pageTaskImpl{
-					baseDestination:   p.BaseDestination,
-					title:             v.Title,
-					description:       v.Description,
-					slug:              v.Slug,
-					source:            v.File,
-					license:           v.License,
-					toc:               v.Toc,
-					search:            v.Search,
-					extraDependencies: deps,
-					prettyURL:         p.PrettyURL,
-					template:          tmpl,
-					templateCtx:       p.TemplateCtx,
-					layoutCtx: &templates.LayoutContext{
-						WithSidebar: p.WithSidebar,
-					},
-					openGraph:         v.OpenGraph,
-					openGraphImageGen: p.OpenGraphImageGen,
-				},
-			),
-		)
+	for i, v := range srcs {
+		impl := &pageTaskImpl{
+			baseDestination: p.BaseDestination,
+			title:           v.Title,
+			description:     v.Description,
+			slug:            v.Slug,
+			source:          v.File,
+			license:         v.License,
+			toc:             v.Toc,
+			search:          v.Search,
+			prettyURL:       p.PrettyURL,
+			template:        tmpl,
+			templateCtx:     p.TemplateCtx,
+			layoutCtx: &templates.LayoutContext{
+				WithSidebar: p.WithSidebar,
+			},
+			section:           nodes[i].section(roots),
+			openGraph:         v.OpenGraph,
+			openGraphImageGen: p.OpenGraphImageGen,
+		}
+
+		// the section includes the titles and urls of the relatives of the
+		// page, it only depends on them, and on the pages being added or removed
+		dep, err := writeState(filepath.Join(p.StateDirectory, "sections", p.BaseDestination, impl.GetDestination()+".json"), impl.section)
+		if err != nil {
+			return nil, err
+		}
+		impl.extraDependencies = append(slices.Clone(deps), dep)
+
+		rv = append(rv, runner.NewTask(p, impl))
 	}
 	return rv, nil
 }
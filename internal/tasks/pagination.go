@@ -31,6 +31,7 @@ type paginationTaskImpl struct {
 	templateCtx     map[string]any
 	pagination      *templates.ContentPagination
 	layoutCtx       *templates.LayoutContext
+	author          *templates.ContentAuthor
 
 	openGraph                    *opengraph.Config
 	openGraphImageGen            *opengraph.OpenGraphImageGen
@@ -58,12 +59,45 @@ func (t *paginationTaskImpl) GetGenerator() (runner.Generator, error) {
 		TemplateCtx:                  t.templateCtx,
 		Pagination:                   t.pagination,
 		LayoutCtx:                    t.layoutCtx,
+		Author:                       t.author,
 		OpenGraph:                    t.openGraph,
 		OpenGraphImageGen:            t.openGraphImageGen,
 		OpenGraphPregeneratedBaseUrl: t.openGraphPregeneratedBaseUrl,
 	}, nil
 }
 
+func listPaginationPosts(dirs []*PostsSources, sortReverse bool) ([]*paginationPost, error) {
+	rv := []*paginationPost{}
+	for _, dir := range dirs {
+		srcs, err := dir.List()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, src := range srcs {
+			post := &paginationPost{
+				Source: src,
+			}
+
+			m, err := content.GetMetadata(post.Source.File)
+			if err != nil {
+				return nil, err
+			}
+
+			post.Published = m.Published.Time
+			rv = append(rv, post)
+		}
+	}
+
+	slices.SortStableFunc(rv, func(a *paginationPost, b *paginationPost) int {
+		if sortReverse {
+			return b.Published.Compare(a.Published)
+		}
+		return a.Published.Compare(b.Published)
+	})
+	return rv, nil
+}
+
 type Pagination struct {
 	Atom            bool
 	Title           string
@@ -76,6 +110,9 @@ type Pagination struct {
 	TemplateCtx     map[string]any
 	WithSidebar     bool
 
+	// only posts by the author are listed, if defined
+	Author *templates.ContentAuthor
+
 	OpenGraph         *opengraph.Config
 	OpenGraphImageGen *opengraph.OpenGraphImageGen
 }
@@ -97,35 +134,25 @@ func (p *Pagination) GetTasks() ([]*runner.Task, error) {
 		}
 	}
 
-	posts := []*paginationPost{}
-	for _, dir := range p.SourceDirs {
-		srcs, err := dir.List()
-		if err != nil {
-			return nil, err
-		}
-
-		for _, src := range srcs {
-			post := &paginationPost{
-				Source: src,
-			}
+	posts, err := listPaginationPosts(p.SourceDirs, p.SortReverse)
+	if err != nil {
+		return nil, err
+	}
 
+	if p.Author != nil {
+		filtered := []*paginationPost{}
+		for _, post := range posts {
 			m, err := content.GetMetadata(post.Source.File)
 			if err != nil {
 				return nil, err
 			}
-
-			post.Published = m.Published.Time
-			posts = append(posts, post)
+			if slices.Contains(m.Authors, p.Author.ID) {
+				filtered = append(filtered, post)
+			}
 		}
+		posts = filtered
 	}
 
-	slices.SortStableFunc(posts, func(a *paginationPost, b *paginationPost) int {
-		if p.SortReverse {
-			return b.Published.Compare(a.Published)
-		}
-		return a.Published.Compare(b.Published)
-	})
-
 	ppp := p.PostsPerPage
 	if ppp < 0 {
 		ppp = len(posts)
@@ -160,6 +187,7 @@ func (p *Pagination) GetTasks() ([]*runner.Task, error) {
 						AtomURL: path.Join("/", p.BaseDestination, "atom.xml"),
 					},
 					layoutCtx:         layoutCtx,
+					author:            p.Author,
 					openGraph:         p.OpenGraph,
 					openGraphImageGen: imageGen,
 				},
@@ -202,6 +230,7 @@ func (p *Pagination) GetTasks() ([]*runner.Task, error) {
 						templateCtx:       p.TemplateCtx,
 						pagination:        pagination,
 						layoutCtx:         layoutCtx,
+						author:            p.Author,
 						openGraph:         p.OpenGraph,
 						openGraphImageGen: imageGen,
 					},
@@ -229,6 +258,7 @@ func (p *Pagination) GetTasks() ([]*runner.Task, error) {
 					templateCtx:                  p.TemplateCtx,
 					pagination:                   pagination,
 					layoutCtx:                    layoutCtx,
+					author:                       p.Author,
 					openGraph:                    p.OpenGraph,
 					openGraphImageGen:            p.OpenGraphImageGen,
 					openGraphPregeneratedBaseUrl: iurl,
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"rafaelmartins.com/p/website/internal/content"
@@ -18,9 +19,76 @@ import (
 type PostsSources struct {
 	Dir             string
 	BaseDestination string
+	Permalink       string
 }
 
-func (p *PostsSources) List() ([]*generators.ContentSource, error) {
+var reToken = regexp.MustCompile(`:[a-z]+`)
+
+// permalink returns the slug of the post, relative to the base destination,
+// from the permalink pattern. the pattern may use the :year, :month and :day
+// of the publication date, the :slug from frontmatter (defaults to the file
+// name) and the :filename tokens.
+func (p *PostsSources) permalink(fpath string) (string, error) {
+	name := filepath.Base(fpath)
+	filename := strings.TrimSuffix(name, filepath.Ext(name))
+
+	pattern := p.Permalink
+	if pattern == "" {
+		pattern = ":slug"
+	}
+
+	meta, err := content.GetMetadata(fpath)
+	if err != nil {
+		return "", err
+	}
+
+	slug := filename
+	if meta.Slug != "" {
+		slug = meta.Slug
+	}
+
+	var rerr error
+	rv := reToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		switch token {
+		case ":slug":
+			return slug
+		case ":filename":
+			return filename
+		case ":year", ":month", ":day":
+			if meta.Published.IsZero() {
+				rerr = fmt.Errorf("posts: permalink requires a published date: %s", fpath)
+				return ""
+			}
+			switch token {
+			case ":year":
+				return fmt.Sprintf("%04d", meta.Published.Year())
+			case ":month":
+				return fmt.Sprintf("%02d", meta.Published.Month())
+			}
+			return fmt.Sprintf("%02d", meta.Published.Day())
+		}
+		rerr = fmt.Errorf("posts: invalid permalink token %q: %s", token, pattern)
+		return ""
+	})
+	if rerr != nil {
+		return "", rerr
+	}
+
+	rv = path.Clean(strings.Trim(rv, "/"))
+	if rv == "." || rv == ".." || strings.HasPrefix(rv, "../") {
+		return "", fmt.Errorf("posts: invalid permalink %q: %s", rv, fpath)
+	}
+	return rv, nil
+}
+
+type postSource struct {
+	file       string
+	slug       string
+	legacySlug string
+	aliases    []string
+}
+
+func (p *PostsSources) list() ([]*postSource, error) {
 	if p.Dir == "" {
 		return nil, fmt.Errorf("posts: source dir not defined")
 	}
@@ -32,18 +100,49 @@ func (p *PostsSources) List() ([]*generators.ContentSource, error) {
 		}
 	}
 
-	rv := []*generators.ContentSource{}
+	rv := []*postSource{}
+	seen := map[string]string{}
 	for _, src := range srcs {
 		fpath := filepath.Join(p.Dir, src.Name())
 		if !content.IsSupported(fpath) {
 			continue
 		}
 
-		slug := strings.TrimSuffix(src.Name(), filepath.Ext(src.Name()))
+		slug, err := p.permalink(fpath)
+		if err != nil {
+			return nil, err
+		}
+		meta, err := content.GetMetadata(fpath)
+		if err != nil {
+			return nil, err
+		}
+		if f, found := seen[slug]; found {
+			return nil, fmt.Errorf("posts: duplicated permalink %q: %s, %s", slug, f, fpath)
+		}
+		seen[slug] = fpath
+
+		rv = append(rv, &postSource{
+			file:       fpath,
+			slug:       slug,
+			legacySlug: strings.TrimSuffix(src.Name(), filepath.Ext(src.Name())),
+			aliases:    meta.Aliases,
+		})
+	}
+	return rv, nil
+}
+
+func (p *PostsSources) List() ([]*generators.ContentSource, error) {
+	srcs, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := []*generators.ContentSource{}
+	for _, src := range srcs {
 		rv = append(rv,
 			&generators.ContentSource{
-				File: fpath,
-				URL:  path.Join("/", p.BaseDestination, slug) + "/",
+				File: src.file,
+				URL:  path.Join("/", p.BaseDestination, src.slug) + "/",
 			},
 		)
 	}
@@ -58,11 +157,13 @@ type postTaskImpl struct {
 	template          string
 	templateCtx       map[string]any
 	layoutCtx         *templates.LayoutContext
+	series            *templates.ContentSeries
+	extraDependencies []string
 	openGraphImageGen *opengraph.OpenGraphImageGen
 }
 
 func (t *postTaskImpl) GetDestination() string {
-	return filepath.Join(t.slug, "index.html")
+	return filepath.Join(filepath.FromSlash(t.slug), "index.html")
 }
 
 func (t *postTaskImpl) GetGenerator() (runner.Generator, error) {
@@ -80,6 +181,8 @@ func (t *postTaskImpl) GetGenerator() (runner.Generator, error) {
 		Template:          t.template,
 		TemplateCtx:       t.templateCtx,
 		LayoutCtx:         t.layoutCtx,
+		Series:            t.series,
+		ExtraDependencies: t.extraDependencies,
 		OpenGraphImageGen: t.openGraphImageGen,
 	}, nil
 }
@@ -88,6 +191,7 @@ type Posts struct {
 	SourceDir         PostsSources
 	Toc               bool
 	Template          string
+	TemplateSeries    string
 	TemplateCtx       map[string]any
 	WithSidebar       bool
 	OpenGraphImageGen *opengraph.OpenGraphImageGen
@@ -107,24 +211,78 @@ func (p *Posts) GetTasks() ([]*runner.Task, error) {
 		tmpl = "base.html"
 	}
 
-	srcs, err := p.SourceDir.List()
+	srcs, err := p.SourceDir.list()
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := p.SourceDir.series(srcs)
 	if err != nil {
 		return nil, err
 	}
+	seriesBySource := map[*postSource]*postSeries{}
+	for _, s := range series {
+		for _, part := range s.parts {
+			seriesBySource[part.source] = s
+		}
+	}
 
 	rv := []*runner.Task{}
 	for _, src := range srcs {
-		name := filepath.Base(src.File)
-		slug := strings.TrimSuffix(name, filepath.Ext(name))
+		var (
+			seriesCtx *templates.ContentSeries
+			deps      []string
+		)
+		if s, found := seriesBySource[src]; found {
+			seriesCtx = s.context(p.SourceDir.BaseDestination, src)
+			deps = s.files()
+		}
+
 		rv = append(rv,
 			runner.NewTask(p,
 				&postTaskImpl{
 					baseDestination: p.SourceDir.BaseDestination,
-					slug:            slug,
+					slug:            src.slug,
 					toc:             p.Toc,
-					source:          src,
-					template:        tmpl,
+					source: &generators.ContentSource{
+						File: src.file,
+						URL:  path.Join("/", p.SourceDir.BaseDestination, src.slug) + "/",
+					},
+					template:    tmpl,
+					templateCtx: p.TemplateCtx,
+					layoutCtx: &templates.LayoutContext{
+						WithSidebar: p.WithSidebar,
+					},
+					series:            seriesCtx,
+					extraDependencies: deps,
+					openGraphImageGen: p.OpenGraphImageGen,
+				},
+			),
+		)
+	}
+
+	tmplSeries := p.TemplateSeries
+	if tmplSeries == "" {
+		tmplSeries = "pagination.html"
+	}
+
+	// series index pages are single pages of the series posts
+	for _, s := range series {
+		rv = append(rv,
+			runner.NewTask(p,
+				&paginationTaskImpl{
+					baseDestination: p.SourceDir.BaseDestination,
+					title:           s.title,
+					description:     fmt.Sprintf("All the %d parts of the %q series.", len(s.parts), s.title),
+					sources:         s.sources(),
+					slug:            path.Join("series", s.slug),
+					template:        tmplSeries,
 					templateCtx:     p.TemplateCtx,
+					pagination: &templates.ContentPagination{
+						AtomURL: path.Join("/", p.SourceDir.BaseDestination, "atom.xml"),
+						Current: 1,
+						Total:   1,
+					},
 					layoutCtx: &templates.LayoutContext{
 						WithSidebar: p.WithSidebar,
 					},
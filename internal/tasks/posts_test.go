@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePost(t *testing.T, dir string, name string, frontmatter string) string {
+	t.Helper()
+
+	fn := filepath.Join(dir, name)
+	if err := os.WriteFile(fn, []byte("---\ntitle: "+name+"\n"+frontmatter+"---\nbody\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func TestPermalink(t *testing.T) {
+	dir := t.TempDir()
+	dated := writePost(t, dir, "dated-post.md", "published: 2024-03-05 10:00:00\n")
+	slugged := writePost(t, dir, "slugged-post.md", "published: 2024-03-05 10:00:00\nslug: custom\n")
+	undated := writePost(t, dir, "undated-post.md", "")
+	escaping := writePost(t, dir, "escaping-post.md", "slug: ../../etc\n")
+
+	for _, tt := range []struct {
+		name     string
+		pattern  string
+		file     string
+		expected string
+		err      bool
+	}{
+		{"default", "", dated, "dated-post", false},
+		{"default-slug", "", slugged, "custom", false},
+		{"date", ":year/:month/:day/:slug", dated, "2024/03/05/dated-post", false},
+		{"date-slug", "/:year/:month/:slug/", slugged, "2024/03/custom", false},
+		{"filename", ":year/:filename", slugged, "2024/slugged-post", false},
+		{"static", "posts/:slug", undated, "posts/undated-post", false},
+		{"missing-date", ":year/:slug", undated, "", true},
+		{"invalid-token", ":foo/:slug", dated, "", true},
+		{"empty", "/", dated, "", true},
+		{"parent", "../:slug", dated, "", true},
+		{"parent-slug", ":slug", escaping, "", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PostsSources{
+				Dir:       dir,
+				Permalink: tt.pattern,
+			}
+			got, err := p.permalink(tt.file)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostsList(t *testing.T) {
+	dir := t.TempDir()
+	writePost(t, dir, "foo.md", "published: 2024-03-05 10:00:00\nslug: bar\naliases: [/old/]\n")
+	writePost(t, dir, "baz.md", "published: 2024-03-06 10:00:00\n")
+
+	p := &PostsSources{
+		Dir:             dir,
+		BaseDestination: "blog",
+		Permalink:       ":year/:slug",
+	}
+	srcs, err := p.list()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(srcs) != 2 {
+		t.Fatalf("got %d posts, want 2", len(srcs))
+	}
+	for _, src := range srcs {
+		if filepath.Base(src.file) != "foo.md" {
+			continue
+		}
+		if src.slug != "2024/bar" || src.legacySlug != "foo" || len(src.aliases) != 1 {
+			t.Errorf("unexpected source: %+v", src)
+		}
+	}
+
+	// two posts with the same permalink
+	writePost(t, dir, "bar.md", "published: 2024-03-07 10:00:00\n")
+	if _, err := p.list(); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,249 @@
+package tasks
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/project"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+func sortProjectsOverview(projects []*templates.ProjectsOverviewContentProject, by string) error {
+	byName := func(a *templates.ProjectsOverviewContentProject, b *templates.ProjectsOverviewContentProject) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}
+
+	switch by {
+	case "", "name":
+		slices.SortStableFunc(projects, byName)
+
+	case "stars":
+		slices.SortStableFunc(projects, func(a *templates.ProjectsOverviewContentProject, b *templates.ProjectsOverviewContentProject) int {
+			if a.Stars != b.Stars {
+				return b.Stars - a.Stars
+			}
+			return byName(a, b)
+		})
+
+	case "release":
+		// projects without releases go last
+		slices.SortStableFunc(projects, func(a *templates.ProjectsOverviewContentProject, b *templates.ProjectsOverviewContentProject) int {
+			if a.LatestReleaseDate == nil || b.LatestReleaseDate == nil {
+				if a.LatestReleaseDate != nil {
+					return -1
+				}
+				if b.LatestReleaseDate != nil {
+					return 1
+				}
+				return byName(a, b)
+			}
+			if c := b.LatestReleaseDate.Compare(*a.LatestReleaseDate); c != 0 {
+				return c
+			}
+			return byName(a, b)
+		})
+
+	default:
+		return fmt.Errorf("projects-overview: invalid sort: %s", by)
+	}
+	return nil
+}
+
+type projectsOverviewJsonEntry struct {
+	Group             string     `json:"group,omitempty"`
+	Owner             string     `json:"owner"`
+	Repo              string     `json:"repo"`
+	Name              string     `json:"name"`
+	URL               string     `json:"url"`
+	Description       string     `json:"description,omitempty"`
+	License           string     `json:"license,omitempty"`
+	Stars             int        `json:"stars"`
+	LatestRelease     string     `json:"latest-release,omitempty"`
+	LatestReleaseDate *time.Time `json:"latest-release-date,omitempty"`
+	CDocs             bool       `json:"cdocs"`
+	GoDocs            bool       `json:"godocs"`
+	Dfu               bool       `json:"dfu"`
+	Kicad             bool       `json:"kicad"`
+}
+
+type projectsOverviewTask struct {
+	baseDestination string
+	title           string
+	description     string
+	template        string
+	entry           *templates.ProjectsOverviewContentEntry
+	layoutCtx       *templates.LayoutContext
+	dependencies    []string
+
+	openGraph         *opengraph.Config
+	openGraphImageGen *opengraph.OpenGraphImageGen
+}
+
+func (t *projectsOverviewTask) GetDestination() string {
+	return "index.html"
+}
+
+func (t *projectsOverviewTask) GetGenerator() (runner.Generator, error) {
+	url := path.Join("/", t.baseDestination)
+	if url != "/" {
+		url += "/"
+	}
+
+	return &generators.ProjectsOverview{
+		Title:       t.title,
+		Description: t.description,
+		URL:         url,
+		Template:    t.template,
+		Entry:       t.entry,
+		LayoutCtx:   t.layoutCtx,
+
+		ExtraDependencies: t.dependencies,
+
+		OpenGraph:         t.openGraph,
+		OpenGraphImageGen: t.openGraphImageGen,
+	}, nil
+}
+
+type projectsOverviewJsonTask struct {
+	entries      []*projectsOverviewJsonEntry
+	dependencies []string
+}
+
+func (t *projectsOverviewJsonTask) GetDestination() string {
+	return "index.json"
+}
+
+func (t *projectsOverviewJsonTask) GetGenerator() (runner.Generator, error) {
+	return &generators.Json{
+		Data:              t.entries,
+		ExtraDependencies: t.dependencies,
+	}, nil
+}
+
+type ProjectsOverviewGroup struct {
+	Title    string
+	Projects []*project.Project
+}
+
+type ProjectsOverview struct {
+	Title           string
+	Description     string
+	Groups          []*ProjectsOverviewGroup
+	Group           bool
+	Sort            string
+	BaseDestination string
+	Template        string
+	WithSidebar     bool
+
+	OpenGraph         *opengraph.Config
+	OpenGraphImageGen *opengraph.OpenGraphImageGen
+}
+
+func (p *ProjectsOverview) GetBaseDestination() string {
+	if p.BaseDestination == "" {
+		return "projects"
+	}
+	return p.BaseDestination
+}
+
+func (p *ProjectsOverview) GetTasks() ([]*runner.Task, error) {
+	tmpl := p.Template
+	if tmpl == "" {
+		tmpl = "projects.html"
+	}
+
+	title := p.Title
+	if title == "" {
+		title = "Projects"
+	}
+
+	description := p.Description
+	if description == "" {
+		description = "Overview of all the projects"
+	}
+
+	entry := &templates.ProjectsOverviewContentEntry{
+		Sort:    p.Sort,
+		JsonURL: path.Join("/", p.GetBaseDestination(), "index.json"),
+	}
+	if entry.Sort == "" {
+		entry.Sort = "name"
+	}
+
+	entries := []*projectsOverviewJsonEntry{}
+	deps := []string{}
+	for _, group := range p.Groups {
+		if group == nil {
+			continue
+		}
+
+		// without grouping, every project goes to a single untitled group
+		if !p.Group || len(entry.Groups) == 0 {
+			g := &templates.ProjectsOverviewContentGroup{}
+			if p.Group {
+				g.Title = group.Title
+			}
+			entry.Groups = append(entry.Groups, g)
+		}
+		g := entry.Groups[len(entry.Groups)-1]
+
+		for _, proj := range group.Projects {
+			ov, err := proj.GetOverview()
+			if err != nil {
+				return nil, err
+			}
+			g.Projects = append(g.Projects, ov)
+			deps = append(deps, proj.GetOverviewPaths()...)
+
+			entries = append(entries, &projectsOverviewJsonEntry{
+				Group:             group.Title,
+				Owner:             ov.Owner,
+				Repo:              ov.Repo,
+				Name:              ov.Name,
+				URL:               ov.URL,
+				Description:       ov.Description,
+				License:           ov.License,
+				Stars:             ov.Stars,
+				LatestRelease:     ov.LatestRelease,
+				LatestReleaseDate: ov.LatestReleaseDate,
+				CDocs:             ov.CDocs,
+				GoDocs:            ov.GoDocs,
+				Dfu:               ov.Dfu,
+				Kicad:             ov.Kicad,
+			})
+		}
+	}
+
+	for _, g := range entry.Groups {
+		if err := sortProjectsOverview(g.Projects, entry.Sort); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*runner.Task{
+		runner.NewTask(p, &projectsOverviewTask{
+			baseDestination: p.GetBaseDestination(),
+			title:           title,
+			description:     description,
+			template:        tmpl,
+			entry:           entry,
+			layoutCtx: &templates.LayoutContext{
+				WithSidebar: p.WithSidebar,
+			},
+			dependencies: deps,
+
+			openGraph:         p.OpenGraph,
+			openGraphImageGen: p.OpenGraphImageGen,
+		}),
+		runner.NewTask(p, &projectsOverviewJsonTask{
+			entries:      entries,
+			dependencies: deps,
+		}),
+	}, nil
+}
@@ -0,0 +1,241 @@
+package tasks
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/content"
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/webserver"
+)
+
+type redirectTaskImpl struct {
+	from              string
+	url               string
+	extraDependencies []string
+}
+
+func (t *redirectTaskImpl) GetDestination() string {
+	dest := filepath.FromSlash(strings.TrimPrefix(t.from, "/"))
+	if path.Ext(t.from) != "" {
+		return dest
+	}
+	return filepath.Join(dest, "index.html")
+}
+
+func (t *redirectTaskImpl) GetGenerator() (runner.Generator, error) {
+	return &generators.Redirect{
+		URL:               t.url,
+		Template:          "redirect.html",
+		ExtraDependencies: t.extraDependencies,
+	}, nil
+}
+
+type redirectsTaskImpl struct {
+	format            string
+	destination       string
+	rules             []*generators.RedirectRule
+	extraDependencies []string
+}
+
+func (t *redirectsTaskImpl) GetDestination() string {
+	return t.destination
+}
+
+func (t *redirectsTaskImpl) GetGenerator() (runner.Generator, error) {
+	return &generators.Redirects{
+		Format:            t.format,
+		Rules:             t.rules,
+		ExtraDependencies: t.extraDependencies,
+	}, nil
+}
+
+type Redirect struct {
+	From string
+	To   string
+}
+
+type redirectSource struct {
+	from string
+	to   string
+	file string
+}
+
+// Redirects generates html stubs for the old urls of the content, from the
+// configuration, the frontmatter aliases and the posts renamed by their
+// permalinks. the redirects are also emitted for some web servers and hosting
+// providers, and served by the development web server.
+type Redirects struct {
+	Redirects []*Redirect
+	Pages     []*Pages
+	Posts     []*PostsSources
+}
+
+func (*Redirects) GetBaseDestination() string {
+	return ""
+}
+
+func redirectFrom(from string) (string, error) {
+	rv := path.Clean("/" + from)
+	if rv == "/" {
+		return "", fmt.Errorf("redirects: invalid source: %q", from)
+	}
+	if path.Ext(rv) == "" {
+		rv += "/"
+	}
+	return rv, nil
+}
+
+// sources returns the redirects, and all the content files that may declare
+// aliases. redirects can't replace generated pages and posts, as they are
+// matched first by the development web server.
+func (r *Redirects) sources() ([]*redirectSource, []string, error) {
+	rv := []*redirectSource{}
+	files := []string{}
+	generated := map[string]bool{}
+	for _, rd := range r.Redirects {
+		rv = append(rv, &redirectSource{
+			from: rd.From,
+			to:   rd.To,
+		})
+	}
+
+	for _, p := range r.Pages {
+		srcs, err := p.sources()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, src := range srcs {
+			files = append(files, src.File)
+			generated[pageURL(p.BaseDestination, src.Slug, p.PrettyURL)] = true
+
+			meta, err := content.GetMetadata(src.File)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, alias := range meta.Aliases {
+				rv = append(rv, &redirectSource{
+					from: alias,
+					to:   pageURL(p.BaseDestination, src.Slug, p.PrettyURL),
+					file: src.File,
+				})
+			}
+		}
+	}
+
+	for _, p := range r.Posts {
+		srcs, err := p.list()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		slugs := map[string]bool{}
+		for _, src := range srcs {
+			slugs[src.slug] = true
+			files = append(files, src.file)
+			generated[path.Join("/", p.BaseDestination, src.slug)+"/"] = true
+		}
+
+		for _, src := range srcs {
+			url := path.Join("/", p.BaseDestination, src.slug) + "/"
+
+			// keep the old file name based urls working
+			if src.legacySlug != src.slug && !slugs[src.legacySlug] {
+				rv = append(rv, &redirectSource{
+					from: path.Join("/", p.BaseDestination, src.legacySlug),
+					to:   url,
+					file: src.file,
+				})
+			}
+
+			for _, alias := range src.aliases {
+				rv = append(rv, &redirectSource{
+					from: alias,
+					to:   url,
+					file: src.file,
+				})
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, src := range rv {
+		from, err := redirectFrom(src.from)
+		if err != nil {
+			return nil, nil, err
+		}
+		if src.to == "" {
+			return nil, nil, fmt.Errorf("redirects: missing destination: %s", src.from)
+		}
+		if seen[from] {
+			return nil, nil, fmt.Errorf("redirects: duplicated source: %s", from)
+		}
+		if generated[from] {
+			return nil, nil, fmt.Errorf("redirects: source matches generated content: %s", from)
+		}
+		seen[from] = true
+		src.from = from
+	}
+	return rv, files, nil
+}
+
+func (r *Redirects) GetTasks() ([]*runner.Task, error) {
+	srcs, files, err := r.sources()
+	if err != nil {
+		return nil, err
+	}
+
+	rv := []*runner.Task{}
+	rules := []*generators.RedirectRule{}
+	served := map[string]string{}
+	for _, src := range srcs {
+		var extraDeps []string
+		if src.file != "" {
+			extraDeps = []string{src.file}
+		}
+
+		rv = append(rv,
+			runner.NewTask(r,
+				&redirectTaskImpl{
+					from:              src.from,
+					url:               src.to,
+					extraDependencies: extraDeps,
+				},
+			),
+		)
+		rules = append(rules, &generators.RedirectRule{
+			From: src.from,
+			To:   src.to,
+		})
+		served[src.from] = src.to
+	}
+	webserver.SetRedirects(served)
+
+	if len(rules) == 0 {
+		return rv, nil
+	}
+
+	for _, f := range []struct {
+		format      string
+		destination string
+	}{
+		{generators.RedirectsNetlify, "_redirects"},
+		{generators.RedirectsNginx, "nginx-redirects.conf"},
+		{generators.RedirectsApache, ".htaccess"},
+	} {
+		rv = append(rv,
+			runner.NewTask(r,
+				&redirectsTaskImpl{
+					format:            f.format,
+					destination:       f.destination,
+					rules:             rules,
+					extraDependencies: files,
+				},
+			),
+		)
+	}
+	return rv, nil
+}
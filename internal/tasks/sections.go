@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"cmp"
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"rafaelmartins.com/p/website/internal/content"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/templates"
+	"rafaelmartins.com/p/website/internal/utils"
+)
+
+// writeState writes the data to a file in the state directory, if it changed.
+// it is used as a dependency of the files rendering the data, instead of all
+// the sources the data was built from.
+func writeState(fpath string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if err := utils.WriteFileIfChanged(fpath, data); err != nil {
+		return "", err
+	}
+	return fpath, nil
+}
+
+func pageURL(baseDestination string, slug string, prettyURL bool) string {
+	if prettyURL {
+		rv := path.Join("/", baseDestination, slug)
+		if rv != "/" {
+			rv += "/"
+		}
+		return rv
+	}
+	return path.Join("/", baseDestination, slug) + ".html"
+}
+
+type pageNode struct {
+	key      string
+	url      string
+	title    string
+	weight   int
+	menu     string
+	source   *PageSource
+	parent   *pageNode
+	children []*pageNode
+}
+
+func comparePageNodes(a *pageNode, b *pageNode) int {
+	if c := cmp.Compare(a.weight, b.weight); c != 0 {
+		return c
+	}
+	return strings.Compare(a.title, b.title)
+}
+
+func (n *pageNode) link(active bool) *templates.ContentLink {
+	return &templates.ContentLink{
+		Active: active,
+		URL:    n.url,
+		Title:  n.title,
+	}
+}
+
+func (n *pageNode) section(roots []*pageNode) *templates.ContentSection {
+	rv := &templates.ContentSection{}
+	for _, c := range n.children {
+		rv.Children = append(rv.Children, c.link(false))
+	}
+
+	siblings := roots
+	if n.parent != nil {
+		rv.Parent = n.parent.link(false)
+		siblings = n.parent.children
+	}
+	for _, s := range siblings {
+		rv.Siblings = append(rv.Siblings, s.link(s == n))
+	}
+
+	for a := n; a != nil; a = a.parent {
+		rv.Breadcrumbs = append(rv.Breadcrumbs, a.link(a == n))
+	}
+	slices.Reverse(rv.Breadcrumbs)
+	return rv
+}
+
+// tree builds the section hierarchy of the pages from their slugs. index
+// pages represent their parent directory, and pages are children of the
+// nearest ancestor page. nodes are returned in the same order as the sources.
+func (p *Pages) tree(srcs []*PageSource) ([]*pageNode, []*pageNode, error) {
+	nodes := []*pageNode{}
+	byKey := map[string]*pageNode{}
+	for _, src := range srcs {
+		meta, err := content.GetMetadata(src.File)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := src.Slug
+		if !p.PrettyURL {
+			if key == "index" {
+				key = ""
+			} else {
+				key = strings.TrimSuffix(key, "/index")
+			}
+			if _, found := byKey[key]; found {
+				key = src.Slug
+			}
+		}
+
+		title := src.Title
+		if title == "" {
+			title = meta.Title
+		}
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(src.File), filepath.Ext(src.File))
+		}
+
+		n := &pageNode{
+			key:    key,
+			url:    pageURL(p.BaseDestination, src.Slug, p.PrettyURL),
+			title:  title,
+			menu:   meta.Menu,
+			source: src,
+		}
+		if meta.Weight != nil {
+			n.weight = *meta.Weight
+		}
+		nodes = append(nodes, n)
+		byKey[key] = n
+	}
+
+	roots := []*pageNode{}
+	for _, n := range nodes {
+		for k := n.key; k != ""; {
+			if k = path.Dir(k); k == "." {
+				k = ""
+			}
+			if parent, found := byKey[k]; found {
+				n.parent = parent
+				parent.children = append(parent.children, n)
+				break
+			}
+		}
+		if n.parent == nil {
+			roots = append(roots, n)
+		}
+	}
+
+	for _, n := range nodes {
+		slices.SortStableFunc(n.children, comparePageNodes)
+	}
+	slices.SortStableFunc(roots, comparePageNodes)
+	return nodes, roots, nil
+}
+
+// Menu generates the navbar entries from the frontmatter menu and weight of
+// the pages. it must run before any other task group, as all the templates
+// render the navbar.
+type Menu struct {
+	Pages          []*Pages
+	StateDirectory string
+}
+
+func (*Menu) GetBaseDestination() string {
+	return ""
+}
+
+func (m *Menu) GetTasks() ([]*runner.Task, error) {
+	nodes := []*pageNode{}
+	for _, p := range m.Pages {
+		srcs, err := p.sources()
+		if err != nil {
+			return nil, err
+		}
+
+		n, _, err := p.tree(srcs)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range n {
+			if node.menu != "" {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	slices.SortStableFunc(nodes, comparePageNodes)
+
+	entries := map[*pageNode]*templates.MenuEntry{}
+	for _, n := range nodes {
+		entries[n] = &templates.MenuEntry{
+			Title: n.menu,
+			URL:   n.url,
+		}
+	}
+
+	// pages are nested in the nearest ancestor page that is also in the menu
+	rv := []*templates.MenuEntry{}
+	for _, n := range nodes {
+		var parent *templates.MenuEntry
+		for a := n.parent; a != nil && parent == nil; a = a.parent {
+			parent = entries[a]
+		}
+		if parent != nil {
+			parent.Children = append(parent.Children, entries[n])
+		} else {
+			rv = append(rv, entries[n])
+		}
+	}
+
+	// the templates only depend on the generated entries, not on the pages
+	dep, err := writeState(filepath.Join(m.StateDirectory, "menu.json"), rv)
+	if err != nil {
+		return nil, err
+	}
+	templates.SetMenu(rv, []string{dep})
+	return nil, nil
+}
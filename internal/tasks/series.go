@@ -0,0 +1,150 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"rafaelmartins.com/p/website/internal/content"
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/templates"
+)
+
+type postSeriesPart struct {
+	source    *postSource
+	url       string
+	title     string
+	part      *int
+	published time.Time
+}
+
+type postSeries struct {
+	title string
+	slug  string
+	parts []*postSeriesPart
+}
+
+func seriesSlug(title string) string {
+	rv := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && rv.Len() > 0 {
+				rv.WriteByte('-')
+			}
+			rv.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return rv.String()
+}
+
+func comparePostSeriesParts(a *postSeriesPart, b *postSeriesPart) int {
+	// explicit parts come first
+	switch {
+	case a.part != nil && b.part != nil:
+		return cmp.Compare(*a.part, *b.part)
+	case a.part != nil:
+		return -1
+	case b.part != nil:
+		return 1
+	}
+	return a.published.Compare(b.published)
+}
+
+// series groups the posts by the series declared in their frontmatter,
+// ordered by the explicit part numbers or by publication date.
+func (p *PostsSources) series(srcs []*postSource) ([]*postSeries, error) {
+	rv := []*postSeries{}
+	slugs := map[string]*postSeries{}
+	for _, src := range srcs {
+		meta, err := content.GetMetadata(src.file)
+		if err != nil {
+			return nil, err
+		}
+		if meta.Series == "" {
+			continue
+		}
+
+		slug := seriesSlug(meta.Series)
+		if slug == "" {
+			return nil, fmt.Errorf("posts: invalid series title %q: %s", meta.Series, src.file)
+		}
+
+		s, found := slugs[slug]
+		if !found {
+			s = &postSeries{
+				title: meta.Series,
+				slug:  slug,
+			}
+			slugs[slug] = s
+			rv = append(rv, s)
+		}
+		if s.title != meta.Series {
+			return nil, fmt.Errorf("posts: conflicting series titles %q and %q: %s", s.title, meta.Series, src.file)
+		}
+
+		s.parts = append(s.parts, &postSeriesPart{
+			source:    src,
+			url:       path.Join("/", p.BaseDestination, src.slug) + "/",
+			title:     meta.Title,
+			part:      meta.SeriesPart,
+			published: meta.Published.Time,
+		})
+	}
+
+	for _, s := range rv {
+		slices.SortStableFunc(s.parts, comparePostSeriesParts)
+	}
+	return rv, nil
+}
+
+func (s *postSeries) url(baseDestination string) string {
+	return path.Join("/", baseDestination, "series", s.slug) + "/"
+}
+
+func (s *postSeries) files() []string {
+	rv := []string{}
+	for _, part := range s.parts {
+		rv = append(rv, part.source.file)
+	}
+	return rv
+}
+
+func (s *postSeries) sources() []*generators.ContentSource {
+	rv := []*generators.ContentSource{}
+	for _, part := range s.parts {
+		rv = append(rv, &generators.ContentSource{
+			File: part.source.file,
+			URL:  part.url,
+		})
+	}
+	return rv
+}
+
+// context returns the series template context for one of its posts.
+func (s *postSeries) context(baseDestination string, src *postSource) *templates.ContentSeries {
+	rv := &templates.ContentSeries{
+		Title: s.title,
+		URL:   s.url(baseDestination),
+		Total: len(s.parts),
+	}
+	for i, part := range s.parts {
+		active := part.source == src
+		if active {
+			rv.Current = i + 1
+		}
+		rv.Parts = append(rv.Parts, &templates.ContentLink{
+			Active: active,
+			URL:    part.url,
+			Title:  part.title,
+		})
+	}
+	return rv
+}
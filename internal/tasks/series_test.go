@@ -0,0 +1,152 @@
+package tasks
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSeriesSlug(t *testing.T) {
+	for _, tt := range []struct {
+		title    string
+		expected string
+	}{
+		{"Build Log", "build-log"},
+		{"  Build -- Log!  ", "build-log"},
+		{"Part 2: the return", "part-2-the-return"},
+		{"Café Über", "café-über"},
+		{"!!!", ""},
+	} {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := seriesSlug(tt.title); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComparePostSeriesParts(t *testing.T) {
+	one, two := 1, 2
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name     string
+		a        *postSeriesPart
+		b        *postSeriesPart
+		expected int
+	}{
+		{"explicit", &postSeriesPart{part: &one, published: late}, &postSeriesPart{part: &two, published: early}, -1},
+		{"explicit-reverse", &postSeriesPart{part: &two}, &postSeriesPart{part: &one}, 1},
+		{"explicit-first", &postSeriesPart{part: &two, published: late}, &postSeriesPart{published: early}, -1},
+		{"explicit-last", &postSeriesPart{published: early}, &postSeriesPart{part: &one, published: late}, 1},
+		{"date", &postSeriesPart{published: early}, &postSeriesPart{published: late}, -1},
+		{"date-reverse", &postSeriesPart{published: late}, &postSeriesPart{published: early}, 1},
+		{"equal", &postSeriesPart{published: early}, &postSeriesPart{published: early}, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := comparePostSeriesParts(tt.a, tt.b); got != tt.expected {
+				t.Errorf("got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSeries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		posts    map[string]string
+		expected map[string][]string
+		err      bool
+	}{
+		{
+			name: "date",
+			posts: map[string]string{
+				"b.md": "series: Build Log\npublished: 2024-02-01 10:00:00\n",
+				"a.md": "series: Build Log\npublished: 2024-03-01 10:00:00\n",
+				"c.md": "series: Build Log\npublished: 2024-01-01 10:00:00\n",
+				"d.md": "published: 2024-01-01 10:00:00\n",
+			},
+			expected: map[string][]string{
+				"build-log": {"/blog/c/", "/blog/b/", "/blog/a/"},
+			},
+		},
+		{
+			name: "explicit",
+			posts: map[string]string{
+				"a.md": "series: Build Log\nseries-part: 2\npublished: 2024-01-01 10:00:00\n",
+				"b.md": "series: Build Log\nseries-part: 1\npublished: 2024-02-01 10:00:00\n",
+				"c.md": "series: Build Log\npublished: 2023-01-01 10:00:00\n",
+				"d.md": "series: Other\n",
+			},
+			expected: map[string][]string{
+				"build-log": {"/blog/b/", "/blog/a/", "/blog/c/"},
+				"other":     {"/blog/d/"},
+			},
+		},
+		{
+			name: "slug-collision",
+			posts: map[string]string{
+				"a.md": "series: Build Log\n",
+				"b.md": "series: build log!\n",
+			},
+			err: true,
+		},
+		{
+			name: "invalid-title",
+			posts: map[string]string{
+				"a.md": "series: \"!!!\"\n",
+			},
+			err: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, fm := range tt.posts {
+				writePost(t, dir, name, fm)
+			}
+
+			p := &PostsSources{
+				Dir:             dir,
+				BaseDestination: "blog",
+			}
+			srcs, err := p.list()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			series, err := p.series(srcs)
+			if tt.err {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(series) != len(tt.expected) {
+				t.Fatalf("got %d series, want %d", len(series), len(tt.expected))
+			}
+			for _, s := range series {
+				urls := []string{}
+				for _, part := range s.parts {
+					urls = append(urls, part.url)
+				}
+				exp, found := tt.expected[s.slug]
+				if !found {
+					t.Fatalf("unexpected series: %s", s.slug)
+				}
+				if !slices.Equal(urls, exp) {
+					t.Fatalf("%s: got %v, want %v", s.slug, urls, exp)
+				}
+
+				ctx := s.context("blog", s.parts[0].source)
+				if ctx.Current != 1 || ctx.Total != len(exp) || ctx.URL != "/blog/series/"+s.slug+"/" || !ctx.Parts[0].Active {
+					t.Errorf("%s: bad context: %+v", s.slug, ctx)
+				}
+			}
+		})
+	}
+}
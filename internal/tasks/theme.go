@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"maps"
+	"slices"
+
+	"rafaelmartins.com/p/website/internal/generators"
+	"rafaelmartins.com/p/website/internal/runner"
+	"rafaelmartins.com/p/website/internal/theme"
+)
+
+type themeAssetTaskImpl struct {
+	path string
+	dest string
+}
+
+func (t *themeAssetTaskImpl) GetDestination() string {
+	return t.dest
+}
+
+func (t *themeAssetTaskImpl) GetGenerator() (runner.Generator, error) {
+	return generators.File(t.path), nil
+}
+
+// ThemeAssets copies the static assets from the theme directories, including
+// the ones overriding embedded assets.
+type ThemeAssets struct {
+	BaseDestination string
+}
+
+func (t *ThemeAssets) GetBaseDestination() string {
+	return t.BaseDestination
+}
+
+func (t *ThemeAssets) GetTasks() ([]*runner.Task, error) {
+	files, err := theme.Files(theme.Assets)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := []*runner.Task{}
+	for _, dest := range slices.Sorted(maps.Keys(files)) {
+		rv = append(rv, runner.NewTask(t, &themeAssetTaskImpl{
+			path: files[dest],
+			dest: dest,
+		}))
+	}
+	return rv, nil
+}
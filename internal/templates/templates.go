@@ -4,18 +4,27 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
+	"path"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
-	"text/template"
+	"sync"
+	ttemplate "text/template"
+	"text/template/parse"
 	"time"
 
 	"rafaelmartins.com/p/website/internal/cdocs"
 	"rafaelmartins.com/p/website/internal/config"
+	"rafaelmartins.com/p/website/internal/godocs"
 	"rafaelmartins.com/p/website/internal/meta"
 	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/theme"
 	"rafaelmartins.com/p/website/internal/utils"
 )
 
@@ -32,43 +41,139 @@ var (
 
 type LayoutContext struct {
 	WithSidebar bool
+	Stylesheets []string
+	Scripts     []string
 }
 
 type AtomContentEntry struct {
 	Updated time.Time
 }
 
+type ContentAuthorLink struct {
+	URL   string
+	Label string
+	Icon  string
+}
+
+type ContentAuthor struct {
+	ID     string
+	Name   string
+	Email  string
+	URL    string
+	Bio    string
+	Avatar string
+	Links  []*ContentAuthorLink
+}
+
 type PostContentEntry struct {
 	Author struct {
 		Name  string
 		Email string
 	}
+	Authors   []*ContentAuthor
 	Published time.Time
 	Updated   time.Time
+	Series    *ContentSeries
 }
 
 type ProjectContentLatestReleaseFile struct {
-	File string
-	URL  string
+	File          string
+	URL           string
+	Size          int64
+	DownloadCount int
 }
 
 type ProjectContentLatestRelease struct {
 	Name  string
 	Tag   string
-	Body  string
+	Body  template.HTML
 	URL   string
 	Files []*ProjectContentLatestReleaseFile
 }
 
+type ProjectContentRelease struct {
+	ID         string
+	Name       string
+	Tag        string
+	Body       template.HTML
+	URL        string
+	Published  time.Time
+	Prerelease bool
+	Latest     bool
+	Files      []*ProjectContentLatestReleaseFile
+}
+
+type ProjectContentBadge struct {
+	Name  string
+	Label string
+	Value string
+	URL   string
+}
+
+type ProjectContentDownloadsFile struct {
+	File       string
+	URL        string
+	Size       int64
+	Mirrored   bool
+	SHA256     string
+	SHA512     string
+	Signatures []*ProjectContentDownloadsFile
+}
+
+type ProjectContentDownloadsRelease struct {
+	ID            string
+	Name          string
+	Tag           string
+	URL           string
+	Published     time.Time
+	Prerelease    bool
+	Latest        bool
+	SHA256SumsURL string
+	SHA512SumsURL string
+	Files         []*ProjectContentDownloadsFile
+}
+
+type ProjectContentMilestone struct {
+	Title        string
+	Body         template.HTML
+	URL          string
+	DueOn        *time.Time
+	OpenIssues   int
+	ClosedIssues int
+	Progress     int
+}
+
+type ProjectContentIssueLabel struct {
+	Name  string
+	Color string
+}
+
+type ProjectContentIssue struct {
+	Number    int
+	Title     string
+	URL       string
+	Created   time.Time
+	Author    string
+	Labels    []*ProjectContentIssueLabel
+	Milestone string
+}
+
+type ProjectContentIssueGroup struct {
+	ID     string
+	Label  string
+	Issues []*ProjectContentIssue
+}
+
 type ProjectContentDocumentation struct {
 	URL   string
 	Label string
 }
 
 type ProjectContentMenu struct {
-	Active bool
-	URL    string
-	Title  string
+	Active   bool
+	URL      string
+	Title    string
+	Children []*ProjectContentMenu
 }
 
 type ProjectContentLicense struct {
@@ -79,21 +184,97 @@ type ProjectContentLicense struct {
 type ProjectContentEntry struct {
 	Owner         string
 	Repo          string
+	Name          string
+	Subdirectory  string
 	URL           string
 	Description   string
 	Menus         []*ProjectContentMenu
+	Breadcrumbs   []*ProjectContentMenu
 	Licenses      []*ProjectContentLicense
 	GoImport      string
 	GoRepo        string
 	CDocsEnabled  bool
 	CDocsURL      string
+	GoDocsURL     string
 	Stars         int
 	Watching      int
 	Forks         int
 	LatestRelease *ProjectContentLatestRelease
+	ReleasesURL   string
+	AtomURL       string
+	SourceURL     string
+	IssuesURL     string
+	DownloadsURL  string
+	Badges        []*ProjectContentBadge
+	Releases      []*ProjectContentRelease
+	Milestones    []*ProjectContentMilestone
+	IssueGroups   []*ProjectContentIssueGroup
+	Downloads     []*ProjectContentDownloadsRelease
 	IsRoot        bool
 }
 
+type SourceContentFile struct {
+	Name  string
+	URL   string
+	IsDir bool
+	Size  int64
+}
+
+type SourceContentEntry struct {
+	Path        string
+	Breadcrumbs []*ProjectContentMenu
+	Entries     []*SourceContentFile
+	Code        template.HTML
+	Language    string
+	Lines       int
+	Size        int64
+	RawURL      string
+	SourceURL   string
+}
+
+type ProjectsOverviewContentProject struct {
+	Owner             string
+	Repo              string
+	Name              string
+	URL               string
+	Description       string
+	License           string
+	Stars             int
+	LatestRelease     string
+	LatestReleaseDate *time.Time
+	CDocs             bool
+	GoDocs            bool
+	Dfu               bool
+	Kicad             bool
+}
+
+type ProjectsOverviewContentGroup struct {
+	Title    string
+	Projects []*ProjectsOverviewContentProject
+}
+
+type ProjectsOverviewContentEntry struct {
+	Sort    string
+	JsonURL string
+	Groups  []*ProjectsOverviewContentGroup
+}
+
+type GoImportContentEntry struct {
+	Prefix        string
+	VCS           string
+	RepoURL       string
+	SourceURL     string
+	SourceDirURL  string
+	SourceFileURL string
+	RedirectURL   string
+	Packages      []string
+}
+
+type RedirectContentEntry struct {
+	URL      string
+	External bool
+}
+
 type OpenGraphEntry struct {
 	Title       string
 	Description string
@@ -101,14 +282,61 @@ type OpenGraphEntry struct {
 }
 
 type ContentEntry struct {
-	File    string
-	URL     string
+	File     string
+	URL      string
+	Title    string
+	Body     template.HTML
+	Post     *PostContentEntry
+	Project  *ProjectContentEntry
+	CDocs    *cdocs.TemplateCtx
+	GoDocs   *godocs.TemplateCtx
+	GoImport *GoImportContentEntry
+	Redirect *RedirectContentEntry
+	Source   *SourceContentEntry
+	Projects *ProjectsOverviewContentEntry
+	Extra    map[string]any
+}
+
+type ContentLink struct {
+	Active bool
+	URL    string
+	Title  string
+}
+
+type ContentSection struct {
+	Parent      *ContentLink
+	Children    []*ContentLink
+	Siblings    []*ContentLink
+	Breadcrumbs []*ContentLink
+}
+
+type ContentSeries struct {
 	Title   string
-	Body    string
-	Post    *PostContentEntry
-	Project *ProjectContentEntry
-	CDocs   *cdocs.TemplateCtx
-	Extra   map[string]any
+	URL     string
+	Current int
+	Total   int
+	Parts   []*ContentLink
+}
+
+type ContentArchiveMonth struct {
+	Month   time.Month
+	URL     string
+	Count   int
+	Entries []*ContentEntry
+}
+
+type ContentArchiveYear struct {
+	Year   int
+	URL    string
+	Count  int
+	Months []*ContentArchiveMonth
+}
+
+type ContentArchive struct {
+	URL   string
+	Year  int
+	Month time.Month
+	Years []*ContentArchiveYear
 }
 
 type ContentPagination struct {
@@ -127,23 +355,99 @@ type ContentContext struct {
 	URL         string
 	Slug        string
 	License     string
-	Toc         string
+	Toc         template.HTML
 	Search      bool
 	OpenGraph   opengraph.TemplateContext
 	Entry       *ContentEntry
 	Entries     []*ContentEntry
 	Atom        *AtomContentEntry
 	Pagination  *ContentPagination
+	Section     *ContentSection
+	Archive     *ContentArchive
+	Author      *ContentAuthor
 	Extra       map[string]any
 }
 
-var gen *meta.Metadata
+type MenuEntry struct {
+	Title    string
+	URL      string
+	Divider  bool
+	Children []*MenuEntry
+}
+
+var (
+	menu     []*MenuEntry
+	menuDeps []string
+	menuM    sync.Mutex
+)
+
+// SetMenu sets the navbar entries generated from the frontmatter of the
+// content, and the files tracking their changes.
+func SetMenu(entries []*MenuEntry, deps []string) {
+	menuM.Lock()
+	defer menuM.Unlock()
+
+	menu = entries
+	menuDeps = deps
+}
+
+// getMenu merges the manual navbar entries from the configuration with the
+// generated ones. manual entries come first and win on duplicated urls.
+func getMenu() []*MenuEntry {
+	rv := []*MenuEntry{}
+	urls := map[string]bool{}
+	for _, m := range ccfg.Menu {
+		entry := &MenuEntry{
+			Title: m.Title,
+			URL:   m.URL,
+		}
+		for _, d := range m.Dropdown {
+			entry.Children = append(entry.Children, &MenuEntry{
+				Title:   d.Title,
+				URL:     d.URL,
+				Divider: d.Divider,
+			})
+		}
+		rv = append(rv, entry)
+		urls[m.URL] = true
+	}
+
+	menuM.Lock()
+	defer menuM.Unlock()
+
+	for _, m := range menu {
+		if !urls[m.URL] {
+			rv = append(rv, m)
+		}
+	}
+	return rv
+}
+
+var (
+	gen  *meta.Metadata
+	genM sync.Mutex
+)
+
+func getGenerator() (*meta.Metadata, error) {
+	genM.Lock()
+	defer genM.Unlock()
+
+	if gen == nil {
+		m, err := meta.GetMetadata()
+		if err != nil {
+			return nil, err
+		}
+		gen = m
+	}
+	return gen, nil
+}
 
 type context struct {
 	Config    *config.Config
 	Generator *meta.Metadata
 	Layout    *LayoutContext
 	Content   *ContentContext
+	Menu      []*MenuEntry
 	Extra     map[string]any
 	Time      time.Time
 	Debug     bool
@@ -151,6 +455,7 @@ type context struct {
 
 func SetConfig(cfg *config.Config) {
 	ccfg = cfg
+	resetCache()
 	if ccfg.Template == nil {
 		var err error
 		content, err = fs.Sub(embedded, "embed")
@@ -169,25 +474,55 @@ func SetDebug(d bool) {
 }
 
 func GetPaths(name string) ([]string, error) {
-	rv := []string{}
-	if ccfg != nil && ccfg.Template != nil {
-		rv = append(rv, *ccfg.Template)
-	} else {
+	xml := filepath.Ext(name) == ".xml"
+	rv, embedded, err := templateFiles(name, xml)
+	if err != nil {
+		return nil, err
+	}
+	if embedded {
 		rv = append(rv, utils.Executable())
 	}
 
-	if _, err := os.Stat(name); err == nil {
-		rv = append(rv, name)
-	} else if _, err := content.Open(name); err == nil {
-		// do nothing, executable timestamp already included
-	} else {
-		return nil, fmt.Errorf("templates: failed to find template: %s", name)
+	// templates added to the theme may override the ones in use
+	rv = append(rv, theme.Directories(theme.Templates)...)
+	if xml {
+		return rv, nil
 	}
 
-	if ccfg != nil {
-		rv = append(rv, ccfg.TemplatePartials...)
+	// the navbar is rendered by all html templates
+	menuM.Lock()
+	defer menuM.Unlock()
+
+	return append(rv, menuDeps...), nil
+}
+
+// GetAuthor returns an author from the registry in the configuration.
+func GetAuthor(id string) (*ContentAuthor, error) {
+	a, found := ccfg.Authors[id]
+	if !found || a == nil {
+		return nil, fmt.Errorf("templates: author not found: %s", id)
 	}
 
+	rv := &ContentAuthor{
+		ID:    id,
+		Name:  a.Name,
+		Email: a.Email,
+		URL:   path.Join("/", ccfg.GetAuthorBaseDestination(id)) + "/",
+		Bio:   a.Bio,
+	}
+	if rv.Name == "" {
+		rv.Name = id
+	}
+	if a.Avatar != "" {
+		rv.Avatar = GetAssetURL(a.Avatar)
+	}
+	for _, l := range a.Links {
+		rv.Links = append(rv.Links, &ContentAuthorLink{
+			URL:   l.URL,
+			Label: l.Label,
+			Icon:  l.Icon,
+		})
+	}
 	return rv, nil
 }
 
@@ -195,6 +530,15 @@ func assetsUrl() string {
 	return "/" + cassetsDir
 }
 
+// GetAssetURL returns the url of an asset. relative names are assets from the
+// assets directory.
+func GetAssetURL(name string) string {
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "://") {
+		return name
+	}
+	return path.Join(assetsUrl(), name)
+}
+
 func required(v reflect.Value) (reflect.Value, error) {
 	if !v.IsValid() {
 		return reflect.Value{}, errors.New("invalid value")
@@ -205,18 +549,19 @@ func required(v reflect.Value) (reflect.Value, error) {
 	return v, nil
 }
 
-func requiredAttr(v reflect.Value) (reflect.Value, error) {
-	r, err := required(v)
-	if err != nil {
-		return reflect.Value{}, err
-	}
-	if r.Kind() == reflect.Interface {
-		r = reflect.ValueOf(r.Interface())
+func fileSize(v int64) string {
+	if v < 1024 {
+		return fmt.Sprintf("%d B", v)
 	}
-	if strings.ContainsAny(r.String(), "\t\n\r\"<>") {
-		return reflect.Value{}, errors.New("value should not contain tabs, new lines, double quotes, html tags")
+
+	f := float64(v)
+	for _, unit := range []string{"KiB", "MiB", "GiB"} {
+		f /= 1024
+		if f < 1024 || unit == "GiB" {
+			return fmt.Sprintf("%.1f %s", f, unit)
+		}
 	}
-	return v, nil
+	return ""
 }
 
 func volatile(v any, ondebug any) any {
@@ -226,58 +571,280 @@ func volatile(v any, ondebug any) any {
 	return v
 }
 
-func Execute(wr io.Writer, name string, fm template.FuncMap, lctx *LayoutContext, cctx *ContentContext) error {
-	if fm == nil {
-		fm = template.FuncMap{}
+var xmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+	"'", "&apos;",
+)
+
+func xmlEscape(args ...any) string {
+	s, ok := "", false
+	if len(args) == 1 {
+		s, ok = args[0].(string)
+	}
+	if !ok {
+		s = fmt.Sprint(args...)
 	}
-	fm["assetsUrl"] = assetsUrl
-	fm["required"] = required
-	fm["requiredAttr"] = requiredAttr
-	fm["volatile"] = volatile
 
-	var tmpl *template.Template
-	if ccfg != nil && ccfg.Template != nil {
+	// drop characters not allowed by xml 1.0
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || (r >= 0x20 && r != 0xfffe && r != 0xffff) {
+			return r
+		}
+		return -1
+	}, s)
+	return xmlReplacer.Replace(s)
+}
+
+// xmlEscapeNode appends the xml escaper to every action that outputs
+// something, like html/template does for html.
+func xmlEscapeNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) == 0 {
+			return
+		}
+		last := n.Pipe.Cmds[len(n.Pipe.Cmds)-1]
+		if id, ok := last.Args[0].(*parse.IdentifierNode); ok && (id.Ident == "xml" || id.Ident == "html") {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("xml").SetPos(n.Pos)},
+		})
+
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, nn := range n.Nodes {
+			xmlEscapeNode(nn)
+		}
+
+	case *parse.IfNode:
+		xmlEscapeNode(n.List)
+		xmlEscapeNode(n.ElseList)
+
+	case *parse.RangeNode:
+		xmlEscapeNode(n.List)
+		xmlEscapeNode(n.ElseList)
+
+	case *parse.WithNode:
+		xmlEscapeNode(n.List)
+		xmlEscapeNode(n.ElseList)
+	}
+}
+
+func parseTemplates(name string, fm template.FuncMap, xml bool) (*ttemplate.Template, error) {
+	var tmpl *ttemplate.Template
+
+	// the base html template is useless for xml outputs
+	if ccfg != nil && ccfg.Template != nil && !xml {
 		var err error
-		tmpl, err = template.New("base").Funcs(fm).ParseFiles(*ccfg.Template)
+		tmpl, err = ttemplate.New("base").Funcs(fm).ParseFiles(*ccfg.Template)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if tmpl == nil {
-		tmpl = template.New("base").Funcs(fm)
+		tmpl = ttemplate.New("base").Funcs(fm)
 
 		// autoload base unless it is the main template
-		if name != "base.html" {
-			t, err := tmpl.ParseFS(content, "base.html")
+		if name != "base.html" && !xml {
+			var (
+				t   *ttemplate.Template
+				err error
+			)
+			if fn := theme.Lookup(theme.Templates, "base.html"); fn != "" {
+				t, err = tmpl.ParseFiles(fn)
+			} else {
+				t, err = tmpl.ParseFS(content, "base.html")
+			}
 			if err != nil {
-				return err
+				return nil, err
 			}
 			tmpl = t
 		}
 	}
 
-	if _, err := os.Stat(name); err == nil {
-		tmpl, err = tmpl.ParseFiles(name)
+	if fn := lookup(name); fn != "" {
+		var err error
+		tmpl, err = tmpl.ParseFiles(fn)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else if _, err := content.Open(name); err == nil {
 		tmpl, err = tmpl.ParseFS(content, name)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
-		return fmt.Errorf("templates: failed to find template: %s", name)
+		return nil, fmt.Errorf("templates: failed to find template: %s", name)
 	}
 
 	if ccfg != nil && len(ccfg.TemplatePartials) > 0 {
 		var err error
 		tmpl, err = tmpl.ParseFiles(ccfg.TemplatePartials...)
 		if err != nil {
-			return err
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+type cacheEntry struct {
+	files  []string
+	mtimes []time.Time
+	html   *template.Template
+	xml    *ttemplate.Template
+}
+
+var (
+	cache  = map[string]*cacheEntry{}
+	cacheM sync.Mutex
+)
+
+func resetCache() {
+	cacheM.Lock()
+	defer cacheM.Unlock()
+
+	clear(cache)
+}
+
+// lookup returns the path of the template in the filesystem, either as given
+// or from the theme directories. an empty string means the embedded template.
+func lookup(name string) string {
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	return theme.Lookup(theme.Templates, name)
+}
+
+// templateFiles returns the files from the filesystem used to build the
+// template set, and if any embedded template is used. embedded templates only
+// change with the executable.
+func templateFiles(name string, xml bool) ([]string, bool, error) {
+	rv := []string{}
+	embedded := false
+
+	// the base html template is useless for xml outputs
+	if ccfg != nil && ccfg.Template != nil {
+		if !xml {
+			rv = append(rv, *ccfg.Template)
+		}
+	} else if !xml && name != "base.html" {
+		if fn := theme.Lookup(theme.Templates, "base.html"); fn != "" {
+			rv = append(rv, fn)
+		} else {
+			embedded = true
 		}
 	}
 
+	if fn := lookup(name); fn != "" {
+		rv = append(rv, fn)
+	} else if _, err := content.Open(name); err == nil {
+		embedded = true
+	} else {
+		return nil, false, fmt.Errorf("templates: failed to find template: %s", name)
+	}
+
+	if ccfg != nil {
+		rv = append(rv, ccfg.TemplatePartials...)
+	}
+	return rv, embedded, nil
+}
+
+func getCacheEntry(name string, fm template.FuncMap, xml bool) (*cacheEntry, error) {
+	// the functions available must be known when parsing, but their
+	// implementations are replaced on each execution.
+	funcs := slices.Sorted(maps.Keys(fm))
+	key := name + "\x00" + strings.Join(funcs, ",")
+
+	files, _, err := templateFiles(name, xml)
+	if err != nil {
+		return nil, err
+	}
+
+	mtimes := []time.Time{}
+	for _, f := range files {
+		st, err := os.Stat(f)
+		if err != nil {
+			return nil, err
+		}
+		mtimes = append(mtimes, st.ModTime())
+	}
+
+	cacheM.Lock()
+	defer cacheM.Unlock()
+
+	if ce, ok := cache[key]; ok && slices.Equal(ce.files, files) && slices.EqualFunc(ce.mtimes, mtimes, time.Time.Equal) {
+		return ce, nil
+	}
+
+	ttmpl, err := parseTemplates(name, fm, xml)
+	if err != nil {
+		return nil, err
+	}
+
+	ce := &cacheEntry{
+		files:  files,
+		mtimes: mtimes,
+	}
+
+	if xml {
+		for _, t := range ttmpl.Templates() {
+			if t.Tree != nil {
+				xmlEscapeNode(t.Tree.Root)
+			}
+		}
+		// the base template may have been replaced by a define, clone from it
+		ce.xml = ttmpl.Lookup("base")
+		if ce.xml == nil {
+			return nil, fmt.Errorf("templates: base template not defined: %s", name)
+		}
+	} else {
+		// html/template escapes copies of the trees when cloning, the
+		// cached set is never executed.
+		ce.html = template.New("base").Funcs(fm)
+		for _, t := range ttmpl.Templates() {
+			if t.Tree == nil {
+				continue
+			}
+			if _, err := ce.html.AddParseTree(t.Name(), t.Tree); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cache[key] = ce
+	return ce, nil
+}
+
+// Execute renders the template. html templates are rendered with
+// html/template contextual escaping, while xml templates (e.g. atom feeds)
+// have all their outputs xml-escaped. trusted html must be passed as
+// template.HTML. parsed templates are cached and cloned for each execution.
+func Execute(wr io.Writer, name string, fm template.FuncMap, lctx *LayoutContext, cctx *ContentContext) error {
+	if fm == nil {
+		fm = template.FuncMap{}
+	}
+	fm["assetsUrl"] = assetsUrl
+	fm["fileSize"] = fileSize
+	fm["required"] = required
+	fm["requiredAttr"] = required // attributes are escaped by html/template
+	fm["volatile"] = volatile
+	fm["xml"] = xmlEscape
+
+	xml := filepath.Ext(name) == ".xml"
+
+	ce, err := getCacheEntry(name, fm, xml)
+	if err != nil {
+		return err
+	}
+
 	llctx := lctx
 	if llctx == nil {
 		llctx = &LayoutContext{}
@@ -288,21 +855,33 @@ func Execute(wr io.Writer, name string, fm template.FuncMap, lctx *LayoutContext
 		lcctx = &ContentContext{}
 	}
 
-	if gen == nil {
-		m, err := meta.GetMetadata()
-		if err != nil {
-			return err
-		}
-		gen = m
+	g, err := getGenerator()
+	if err != nil {
+		return err
 	}
 
-	return tmpl.Option("missingkey=zero").ExecuteTemplate(wr, "base", &context{
+	ctx := &context{
 		Config:    ccfg,
-		Generator: gen,
+		Generator: g,
 		Layout:    llctx,
 		Content:   lcctx,
+		Menu:      getMenu(),
 		Extra:     ccfg.TemplateCtx,
 		Time:      time.Now().UTC(),
 		Debug:     debug,
-	})
+	}
+
+	if xml {
+		tmpl, err := ce.xml.Clone()
+		if err != nil {
+			return err
+		}
+		return tmpl.Funcs(fm).Option("missingkey=zero").ExecuteTemplate(wr, "base", ctx)
+	}
+
+	tmpl, err := ce.html.Clone()
+	if err != nil {
+		return err
+	}
+	return tmpl.Funcs(fm).Option("missingkey=zero").ExecuteTemplate(wr, "base", ctx)
 }
@@ -0,0 +1,734 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+	"rafaelmartins.com/p/website/internal/config"
+	"rafaelmartins.com/p/website/internal/opengraph"
+	"rafaelmartins.com/p/website/internal/theme"
+)
+
+const hostile = `"><script>alert(1)</script>`
+
+func setTestConfig(t *testing.T) {
+	t.Helper()
+
+	cfg := &config.Config{
+		Title:  "site",
+		Footer: "footer",
+		URL:    "https://example.com",
+	}
+	cfg.Author.Name = "author"
+	cfg.Author.Email = "author@example.com"
+	SetConfig(cfg)
+}
+
+// testContentContext fills the fields of the content context that are
+// required by the base template, if not set.
+func testContentContext(c *ContentContext) *ContentContext {
+	if c.Title == "" {
+		c.Title = "title"
+	}
+	if c.URL == "" {
+		c.URL = "/foo/"
+	}
+	if c.OpenGraph.Title == "" {
+		c.OpenGraph.Title = "title"
+	}
+	if c.OpenGraph.Description == "" {
+		c.OpenGraph.Description = "description"
+	}
+	return c
+}
+
+func TestExecuteHtmlEscape(t *testing.T) {
+	setTestConfig(t)
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, &ContentContext{
+		Title:       hostile,
+		Description: hostile,
+		URL:         "/foo/",
+		Toc:         "<ul><li>toc</li></ul>",
+		OpenGraph: opengraph.TemplateContext{
+			Title:       hostile,
+			Description: hostile,
+		},
+		Entry: &ContentEntry{
+			Title: hostile,
+			Body:  "<p>trusted <em>body</em></p>",
+			Post: &PostContentEntry{
+				Published: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "<script>alert(1)</script>") {
+		t.Errorf("unescaped value in output:\n%s", out)
+	}
+	for _, s := range []string{
+		`content="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"`,
+		`&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</h1>`,
+		"<ul><li>toc</li></ul>",
+		"<p>trusted <em>body</em></p>",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestExecuteHtmlEscapeExtra(t *testing.T) {
+	setTestConfig(t)
+
+	tmpl := filepath.Join(t.TempDir(), "extra.html")
+	if err := os.WriteFile(tmpl, []byte(`{{ define "main" -}}
+<a href="{{ .Content.Entry.Extra.link }}" title="{{ .Content.Entry.Extra.title }}">{{ .Content.Entry.Extra.title }}</a>
+{{- end }}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, tmpl, nil, nil, testContentContext(&ContentContext{
+		Entry: &ContentEntry{
+			Extra: map[string]any{
+				"link":  "javascript:alert(1)",
+				"title": hostile,
+			},
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	expected := `<a href="#ZgotmplZ" title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</a>`
+	if !strings.Contains(out, expected) {
+		t.Errorf("output missing %q:\n%s", expected, out)
+	}
+}
+
+func TestExecuteFooter(t *testing.T) {
+	setTestConfig(t)
+	ccfg.Footer = `<a href="/about/">About</a> &copy; author`
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, testContentContext(&ContentContext{
+		Entry: &ContentEntry{
+			Title: "title",
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, `<a href="/about/">About</a> &copy; author`) {
+		t.Errorf("footer escaped:\n%s", out)
+	}
+}
+
+func TestExecuteXmlEscape(t *testing.T) {
+	setTestConfig(t)
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "atom.xml", nil, nil, &ContentContext{
+		Title: hostile,
+		URL:   "/foo/",
+		Atom: &AtomContentEntry{
+			Updated: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Pagination: &ContentPagination{
+			AtomURL: "/foo/atom.xml",
+		},
+		Entries: []*ContentEntry{
+			{
+				Title: "a & b\x00",
+				URL:   "/foo/bar/?a=1&b=2",
+				Body:  template.HTML("<p>trusted</p>"),
+				Post: &PostContentEntry{
+					Published: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="utf-8"?>`) {
+		t.Errorf("bad xml declaration:\n%s", out)
+	}
+	if strings.Contains(out, "<script>") || strings.Contains(out, "<!DOCTYPE html>") {
+		t.Errorf("unexpected html in output:\n%s", out)
+	}
+	for _, s := range []string{
+		`<title type="text">site - &quot;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</title>`,
+		`<title type="text">a &amp; b</title>`,
+		`<link href="https://example.com/foo/bar/?a=1&amp;b=2" />`,
+		`<content type="html">&lt;p&gt;trusted&lt;/p&gt;</content>`,
+		`<updated>2025-01-02T03:04:05Z</updated>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestXmlEscape(t *testing.T) {
+	for _, tc := range []struct {
+		args     []any
+		expected string
+	}{
+		{[]any{`<a href="x">'b' & c</a>`}, "&lt;a href=&quot;x&quot;&gt;&apos;b&apos; &amp; c&lt;/a&gt;"},
+		{[]any{"a\tb\nc\x00\x1bd"}, "a\tb\ncd"},
+		{[]any{template.HTML("<p>")}, "&lt;p&gt;"},
+		{[]any{42}, "42"},
+	} {
+		if got := xmlEscape(tc.args...); got != tc.expected {
+			t.Errorf("xmlEscape(%v): got %q, want %q", tc.args, got, tc.expected)
+		}
+	}
+}
+
+func writeTemplate(t *testing.T, fn string, src string, mtime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(fn, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fn, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteCache(t *testing.T) {
+	setTestConfig(t)
+
+	tmpl := filepath.Join(t.TempDir(), "cache.html")
+	mtime := time.Now().Add(-time.Hour)
+	writeTemplate(t, tmpl, `{{ define "main" }}<p>first</p>{{ end }}`, mtime)
+
+	cctx := testContentContext(&ContentContext{})
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, tmpl, nil, nil, cctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "<p>first</p>") {
+		t.Errorf("output missing first:\n%s", buf.String())
+	}
+
+	entry := func() *cacheEntry {
+		cacheM.Lock()
+		defer cacheM.Unlock()
+
+		for k, v := range cache {
+			if strings.HasPrefix(k, tmpl+"\x00") {
+				return v
+			}
+		}
+		return nil
+	}
+
+	ce := entry()
+	if ce == nil {
+		t.Fatalf("template set not cached")
+	}
+	if err := Execute(&bytes.Buffer{}, tmpl, nil, nil, cctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if entry() != ce {
+		t.Errorf("template set parsed twice")
+	}
+
+	writeTemplate(t, tmpl, `{{ define "main" }}<p>second</p>{{ end }}`, mtime.Add(time.Minute))
+
+	buf.Reset()
+	if err := Execute(buf, tmpl, nil, nil, cctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "<p>second</p>") {
+		t.Errorf("output missing second:\n%s", buf.String())
+	}
+}
+
+func TestExecuteCacheFuncs(t *testing.T) {
+	setTestConfig(t)
+
+	tmpl := filepath.Join(t.TempDir(), "funcs.html")
+	writeTemplate(t, tmpl, `{{ define "main" }}<p>{{ foo }}</p>{{ end }}`, time.Now().Add(-time.Hour))
+
+	cctx := testContentContext(&ContentContext{})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	outs := make([]string, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			buf := &bytes.Buffer{}
+			errs[i] = Execute(buf, tmpl, template.FuncMap{
+				"foo": func() int { return i },
+			}, nil, cctx)
+			outs[i] = buf.String()
+		}()
+	}
+	wg.Wait()
+
+	for i := range 20 {
+		if errs[i] != nil {
+			t.Errorf("%d: unexpected error: %s", i, errs[i])
+			continue
+		}
+		if s := fmt.Sprintf("<p>%d</p>", i); !strings.Contains(outs[i], s) {
+			t.Errorf("%d: output missing %q:\n%s", i, s, outs[i])
+		}
+	}
+}
+
+func TestExecuteTheme(t *testing.T) {
+	setTestConfig(t)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(dir, "templates", "entry.html")
+	writeTemplate(t, fn, `{{ define "main" }}<p>themed</p>{{ end }}`, time.Now().Add(-time.Hour))
+
+	if err := theme.SetDirectories([]string{dir}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() {
+		theme.SetDirectories(nil)
+	})
+
+	paths, err := GetPaths("entry.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !slices.Contains(paths, fn) {
+		t.Errorf("theme template not tracked: %v", paths)
+	}
+	if !slices.Contains(paths, filepath.Join(dir, "templates")) {
+		t.Errorf("theme templates directory not tracked: %v", paths)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, testContentContext(&ContentContext{})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "<p>themed</p>") {
+		t.Errorf("output missing themed template:\n%s", out)
+	}
+	if !strings.Contains(out, "<!DOCTYPE html>") {
+		t.Errorf("output missing embedded base template:\n%s", out)
+	}
+}
+
+func TestExecuteLayoutAssets(t *testing.T) {
+	setTestConfig(t)
+	SetAssetsDir("assets")
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, &LayoutContext{
+		WithSidebar: true,
+		Stylesheets: []string{GetAssetURL("landing.css")},
+		Scripts:     []string{GetAssetURL("/js/landing.js"), GetAssetURL("https://example.com/widget.js")},
+	}, testContentContext(&ContentContext{
+		Entry: &ContentEntry{},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		`<link href="/assets/landing.css" rel="stylesheet">`,
+		`<script src="/js/landing.js"></script>`,
+		`<script src="https://example.com/widget.js"></script>`,
+		`column is-8`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestExecuteMenu(t *testing.T) {
+	setTestConfig(t)
+	ccfg.Menu = []*struct {
+		Title    string `yaml:"title"`
+		URL      string `yaml:"url"`
+		Dropdown []struct {
+			Title   string `yaml:"title"`
+			URL     string `yaml:"url"`
+			Divider bool   `yaml:"divider"`
+		} `yaml:"dropdown"`
+	}{
+		{Title: "Manual", URL: "/manual/"},
+		{Title: "About", URL: "/about/"},
+	}
+
+	SetMenu([]*MenuEntry{
+		{Title: "Duplicated", URL: "/about/"},
+		{
+			Title: "Docs",
+			URL:   "/docs/",
+			Children: []*MenuEntry{
+				{Title: "Install", URL: "/docs/install/"},
+			},
+		},
+	}, []string{"foo.md"})
+	t.Cleanup(func() {
+		SetMenu(nil, nil)
+	})
+
+	paths, err := GetPaths("entry.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !slices.Contains(paths, "foo.md") {
+		t.Errorf("menu dependency not tracked: %v", paths)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, testContentContext(&ContentContext{
+		Entry: &ContentEntry{},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "Duplicated") {
+		t.Errorf("duplicated menu entry in output:\n%s", out)
+	}
+	manual := strings.Index(out, `<a class="navbar-item" href="/manual/">Manual</a>`)
+	docs := strings.Index(out, `<a class="navbar-link" href="/docs/">Docs</a>`)
+	install := strings.Index(out, `<a class="navbar-item" href="/docs/install/">Install</a>`)
+	if manual < 0 || docs < 0 || install < 0 || manual > docs || docs > install {
+		t.Errorf("bad menu in output:\n%s", out)
+	}
+}
+
+func TestExecuteSection(t *testing.T) {
+	setTestConfig(t)
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "base.html", nil, nil, testContentContext(&ContentContext{
+		URL:   "/docs/",
+		Entry: &ContentEntry{},
+		Section: &ContentSection{
+			Parent: &ContentLink{Title: "Home", URL: "/"},
+			Children: []*ContentLink{
+				{Title: "Install", URL: "/docs/install/"},
+				{Title: "Usage", URL: "/docs/usage/"},
+			},
+			Breadcrumbs: []*ContentLink{
+				{Title: "Home", URL: "/"},
+				{Title: "Docs", URL: "/docs/", Active: true},
+			},
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		`<nav class="breadcrumb" aria-label="breadcrumbs">`,
+		`<li><a href="/">Home</a></li>`,
+		`<li class="is-active"><a href="/docs/" aria-current="page">Docs</a></li>`,
+		`<li><a href="/docs/install/">Install</a></li>`,
+		`<li><a href="/docs/usage/">Usage</a></li>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestExecuteRedirect(t *testing.T) {
+	setTestConfig(t)
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "redirect.html", nil, nil, &ContentContext{
+		Title: "/foo/bar/",
+		URL:   "/foo/bar/",
+		Entry: &ContentEntry{
+			Redirect: &RedirectContentEntry{
+				URL: "/foo/bar/",
+			},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		`<meta http-equiv="refresh" content="0; url=/foo/bar/">`,
+		`<link rel="canonical" href="https://example.com/foo/bar/">`,
+		`<a href="/foo/bar/">/foo/bar/</a>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "navbar") {
+		t.Errorf("unexpected base template in output:\n%s", out)
+	}
+}
+
+func TestExecuteArchive(t *testing.T) {
+	setTestConfig(t)
+
+	entry := &ContentEntry{
+		Title: "post",
+		URL:   "/blog/post/",
+		Post: &PostContentEntry{
+			Published: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "archive.html", nil, nil, testContentContext(&ContentContext{
+		Title:   "Archive: 2025",
+		URL:     "/blog/archive/2025/",
+		Entries: []*ContentEntry{entry},
+		Archive: &ContentArchive{
+			URL:  "/blog/archive/",
+			Year: 2025,
+			Years: []*ContentArchiveYear{
+				{
+					Year:  2025,
+					URL:   "/blog/archive/2025/",
+					Count: 1,
+					Months: []*ContentArchiveMonth{
+						{
+							Month:   time.March,
+							URL:     "/blog/archive/2025/03/",
+							Count:   1,
+							Entries: []*ContentEntry{entry},
+						},
+					},
+				},
+				{
+					Year:  2024,
+					URL:   "/blog/archive/2024/",
+					Count: 7,
+				},
+			},
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		`<a class="tag" href="/blog/archive/">All</a>`,
+		`<a class="tag is-link" href="/blog/archive/2025/">2025 (1)</a>`,
+		`<a class="tag" href="/blog/archive/2024/">2024 (7)</a>`,
+		`<a class="has-text-link-dark" href="/blog/archive/2025/03/">March</a>`,
+		`<a href="/blog/post/">post</a>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, `<a class="has-text-link-dark" href="/blog/archive/2024/">2024</a>`) {
+		t.Errorf("unexpected year in output:\n%s", out)
+	}
+}
+
+func TestExecuteSeries(t *testing.T) {
+	setTestConfig(t)
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, testContentContext(&ContentContext{
+		Title: "part two",
+		URL:   "/blog/two/",
+		Entry: &ContentEntry{
+			Title: "part two",
+			Post: &PostContentEntry{
+				Published: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				Series: &ContentSeries{
+					Title:   "build log",
+					URL:     "/blog/series/build-log/",
+					Current: 2,
+					Total:   3,
+					Parts: []*ContentLink{
+						{Title: "part one", URL: "/blog/one/"},
+						{Title: "part two", URL: "/blog/two/", Active: true},
+						{Title: "part three", URL: "/blog/three/"},
+					},
+				},
+			},
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		`part 2 of 3 of the series <a href="/blog/series/build-log/">build log</a>`,
+		`<li><a href="/blog/one/">part one</a></li>`,
+		`<li><strong>part two</strong></li>`,
+		`<li><a href="/blog/three/">part three</a></li>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func setTestAuthors(t *testing.T) {
+	t.Helper()
+
+	setTestConfig(t)
+	if err := yaml.Unmarshal([]byte(`
+authors:
+  alice:
+    name: Alice
+    email: alice@example.com
+    bio: writes about hardware.
+    links:
+      - url: https://github.com/alice
+        label: GitHub
+        icon: github
+  bob: {}
+`), ccfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestGetAuthor(t *testing.T) {
+	setTestAuthors(t)
+
+	a, err := GetAuthor("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a.Name != "Alice" || a.Email != "alice@example.com" || a.URL != "/authors/alice/" || len(a.Links) != 1 {
+		t.Errorf("unexpected author: %+v", a)
+	}
+
+	b, err := GetAuthor("bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if b.Name != "bob" || b.URL != "/authors/bob/" {
+		t.Errorf("unexpected author: %+v", b)
+	}
+
+	if _, err := GetAuthor("carol"); err == nil {
+		t.Error("expected error for unknown author")
+	}
+}
+
+func TestExecuteAuthors(t *testing.T) {
+	setTestAuthors(t)
+
+	alice, err := GetAuthor("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	bob, err := GetAuthor("bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Execute(buf, "entry.html", nil, nil, testContentContext(&ContentContext{
+		Title: "post",
+		URL:   "/blog/post/",
+		Entry: &ContentEntry{
+			Title: "post",
+			Post: &PostContentEntry{
+				Authors:   []*ContentAuthor{alice, bob},
+				Published: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := `Published by <a href="/authors/alice/">Alice</a>, <a href="/authors/bob/">bob</a> on`; !strings.Contains(buf.String(), s) {
+		t.Errorf("output missing %q:\n%s", s, buf.String())
+	}
+
+	buf.Reset()
+	if err := Execute(buf, "atom.xml", nil, nil, &ContentContext{
+		Title: "Alice",
+		URL:   "/authors/alice/",
+		Atom: &AtomContentEntry{
+			Updated: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Pagination: &ContentPagination{
+			AtomURL: "/authors/alice/atom.xml",
+		},
+		Author: alice,
+		Entries: []*ContentEntry{
+			{
+				Title: "post",
+				URL:   "/blog/post/",
+				Post: &PostContentEntry{
+					Authors:   []*ContentAuthor{alice, bob},
+					Published: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<name>author</name>") {
+		t.Errorf("unexpected site author in output:\n%s", out)
+	}
+	if c := strings.Count(out, "<name>Alice</name>"); c != 2 {
+		t.Errorf("got %d authors named Alice, want 2:\n%s", c, out)
+	}
+	for _, s := range []string{
+		"<email>alice@example.com</email>",
+		"<uri>https://example.com/authors/alice/</uri>",
+		"<name>bob</name>",
+		"<uri>https://example.com/authors/bob/</uri>",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+
+	buf.Reset()
+	if err := Execute(buf, "pagination.html", nil, nil, testContentContext(&ContentContext{
+		Title:       "Alice",
+		Description: alice.Bio,
+		URL:         "/authors/alice/",
+		Pagination: &ContentPagination{
+			AtomURL: "/authors/alice/atom.xml",
+		},
+		Author: alice,
+	})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, s := range []string{
+		`<p class="has-text-weight-bold">Alice</p>`,
+		`<p>writes about hardware.</p>`,
+		`<a class="mr-2" href="https://github.com/alice" aria-label="GitHub"><i class="fa-brands fa-github" aria-hidden="true"></i></a>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package theme
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	Templates = "templates"
+	Assets    = "assets"
+)
+
+// theme directories, searched in order before the embedded files.
+var dirs []string
+
+func SetDirectories(d []string) error {
+	for _, dir := range d {
+		st, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("theme: %w", err)
+		}
+		if !st.IsDir() {
+			return fmt.Errorf("theme: not a directory: %s", dir)
+		}
+	}
+	dirs = d
+	return nil
+}
+
+func GetDirectories() []string {
+	return dirs
+}
+
+// Lookup returns the path of the first file matching the name in the theme
+// directories, or an empty string if the embedded file should be used.
+func Lookup(kind string, name string) string {
+	for _, dir := range dirs {
+		fn := filepath.Join(dir, kind, name)
+		if st, err := os.Stat(fn); err == nil && st.Mode().IsRegular() {
+			return fn
+		}
+	}
+	return ""
+}
+
+// Directories returns the existing directories of a kind from the theme
+// directories. files added to them may override the ones found by Lookup.
+func Directories(kind string) []string {
+	rv := []string{}
+	for _, dir := range dirs {
+		fn := filepath.Join(dir, kind)
+		if st, err := os.Stat(fn); err == nil && st.IsDir() {
+			rv = append(rv, fn)
+		}
+	}
+	return rv
+}
+
+// Files returns all the files of a kind from the theme directories, keyed by
+// their path relative to the kind directory. files from the first
+// directories win.
+func Files(kind string) (map[string]string, error) {
+	rv := map[string]string{}
+	for _, dir := range slices.Backward(dirs) {
+		base := filepath.Join(dir, kind)
+		if _, err := os.Stat(base); err != nil {
+			continue
+		}
+
+		if err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			rv[rel] = path
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
@@ -0,0 +1,91 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+
+	for _, f := range files {
+		fn := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLayers(t *testing.T) {
+	local := t.TempDir()
+	named := t.TempDir()
+	writeFiles(t, local, "templates/entry.html", "assets/main.css")
+	writeFiles(t, named, "templates/entry.html", "templates/pagination.html", "assets/main.css", "assets/img/logo.svg")
+
+	if err := SetDirectories([]string{local, named}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() {
+		dirs = nil
+	})
+
+	for _, tc := range []struct {
+		kind     string
+		name     string
+		expected string
+	}{
+		{Templates, "entry.html", filepath.Join(local, "templates", "entry.html")},
+		{Templates, "pagination.html", filepath.Join(named, "templates", "pagination.html")},
+		{Templates, "base.html", ""},
+		{Templates, "main.css", ""},
+		{Assets, "main.css", filepath.Join(local, "assets", "main.css")},
+	} {
+		if got := Lookup(tc.kind, tc.name); got != tc.expected {
+			t.Errorf("Lookup(%q, %q): got %q, want %q", tc.kind, tc.name, got, tc.expected)
+		}
+	}
+
+	if got := Directories(Templates); !slices.Equal(got, []string{filepath.Join(local, "templates"), filepath.Join(named, "templates")}) {
+		t.Errorf("Directories(%q): got %v", Templates, got)
+	}
+	if got := Directories("bola"); len(got) != 0 {
+		t.Errorf("Directories(%q): got %v", "bola", got)
+	}
+
+	files, err := Files(Assets)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]string{
+		"main.css":                       filepath.Join(local, "assets", "main.css"),
+		filepath.Join("img", "logo.svg"): filepath.Join(named, "assets", "img", "logo.svg"),
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	for k, v := range expected {
+		if files[k] != v {
+			t.Errorf("Files: %q: got %q, want %q", k, files[k], v)
+		}
+	}
+}
+
+func TestSetDirectoriesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "file")
+
+	if err := SetDirectories([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Errorf("expected error for missing directory")
+	}
+	if err := SetDirectories([]string{filepath.Join(dir, "file")}); err == nil {
+		t.Errorf("expected error for file")
+	}
+	if len(dirs) != 0 {
+		t.Errorf("directories set on error: %v", dirs)
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileIfChanged writes the file only if its content changed, so that
+// its modification time can be used as a dependency of the generated files.
+func WriteFileIfChanged(name string, data []byte) error {
+	if old, err := os.ReadFile(name); err == nil && bytes.Equal(old, data) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0666)
+}
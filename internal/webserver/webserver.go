@@ -5,6 +5,8 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"path"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -51,12 +53,49 @@ func (f *fsWrapper) Open(name string) (http.File, error) {
 	return efp, nil
 }
 
+var (
+	redirects  map[string]string
+	redirectsM sync.Mutex
+)
+
+// SetRedirects sets the redirects served with a permanent redirect status,
+// keyed by source path.
+func SetRedirects(r map[string]string) {
+	redirectsM.Lock()
+	defer redirectsM.Unlock()
+
+	redirects = map[string]string{}
+	for from, to := range r {
+		redirects[path.Clean(from)] = to
+	}
+}
+
+func getRedirect(p string) (string, bool) {
+	redirectsM.Lock()
+	defer redirectsM.Unlock()
+
+	to, found := redirects[path.Clean(p)]
+	return to, found
+}
+
+func FileServer(dir string) http.Handler {
+	return http.FileServer(&fsWrapper{
+		dir: http.Dir(dir),
+	})
+}
+
 func ListenAndServeWithReloader(addr string, dir string, cb func() error) error {
 	exit := make(chan error)
 
+	fileServer := FileServer(dir)
+
 	mux := http.NewServeMux()
-	mux.Handle("/", http.FileServer(&fsWrapper{
-		dir: http.Dir(dir),
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if to, found := getRedirect(r.URL.Path); found {
+			http.Redirect(w, r, to, http.StatusMovedPermanently)
+			return
+		}
+		fileServer.ServeHTTP(w, r)
 	}))
 
 	server := &http.Server{
@@ -4,10 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"rafaelmartins.com/p/website/internal/assets"
+	"rafaelmartins.com/p/website/internal/badge"
 	"rafaelmartins.com/p/website/internal/cdocs"
 	"rafaelmartins.com/p/website/internal/config"
 	"rafaelmartins.com/p/website/internal/govanitychecker"
@@ -20,6 +23,7 @@ import (
 	"rafaelmartins.com/p/website/internal/runner"
 	"rafaelmartins.com/p/website/internal/tasks"
 	"rafaelmartins.com/p/website/internal/templates"
+	"rafaelmartins.com/p/website/internal/theme"
 	"rafaelmartins.com/p/website/internal/webserver"
 )
 
@@ -50,6 +54,7 @@ func stringSlice(name string, usage string) *stringMapFlag {
 
 var (
 	fBuildDir        = flag.String("d", "_build", "build directory")
+	fStateDir        = flag.String("s", "_state", "state directory, used to track changes between builds")
 	fConfigFile      = flag.String("c", "config.yml", "configuration file")
 	fListenAddr      = flag.String("a", ":3000", "development web server listen address")
 	fCDocs           = flag.String("x", "", "dump cdocs ast and template context for given header and exit")
@@ -57,7 +62,7 @@ var (
 	fRunServer       = flag.Bool("r", false, "run development server")
 	fForce           = flag.Bool("f", false, "force re-running all tasks")
 	fDebug           = flag.Bool("b", false, "debug mode: disable post-processing and dynamic strings")
-	fGoVanityChecker = flag.Bool("g", false, "test go vanity urls and exit")
+	fGoVanityChecker = flag.Bool("g", false, "test go vanity urls against the deployed website and exit")
 	fKicad           = flag.Bool("k", false, "kicad assets mode")
 	fVersion         = flag.Bool("v", false, "show version and exit")
 
@@ -81,7 +86,16 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 		return nil, err
 	}
 
-	rv := []*runner.TaskGroup{}
+	// fonts are only fetched when the first badge is rendered
+	badges := badge.NewRenderer(c.Badges)
+
+	// the menu must be generated before anything is rendered
+	menu := &tasks.Menu{
+		StateDirectory: *fStateDir,
+	}
+	rv := []*runner.TaskGroup{
+		runner.NewTaskGroup(menu),
+	}
 
 	if c.Template == nil {
 		// search specific assets embedded
@@ -108,11 +122,11 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 			)
 		}
 
-		// cdocs specific assets embedded
+		// cdocs specific assets embedded, also used by godocs
 		withCDocs := false
 		for _, p := range c.Projects {
 			for _, r := range p.Repositories {
-				if len(r.CDocs.Headers) > 0 {
+				if len(r.CDocs.Headers) > 0 || (r.Go.Docs.Enabled && r.Go.Import != "") {
 					withCDocs = true
 					break
 				}
@@ -222,6 +236,17 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 		)
 	}
 
+	// theme assets, overriding the embedded ones
+	if len(theme.GetDirectories()) > 0 {
+		rv = append(rv,
+			runner.NewTaskGroup(
+				&tasks.ThemeAssets{
+					BaseDestination: assetsDir,
+				},
+			),
+		)
+	}
+
 	for _, js := range c.Assets.Npm {
 		rv = append(rv,
 			runner.NewTaskGroup(
@@ -270,20 +295,21 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 			prettyURL = false
 		}
 
-		rv = append(rv,
-			runner.NewTaskGroup(
-				&tasks.Pages{
-					Sources:           src,
-					ExtraDependencies: pg.ExtraDependencies,
-					PrettyURL:         prettyURL,
-					BaseDestination:   pg.BaseDestination,
-					Template:          pg.Template,
-					TemplateCtx:       pg.TemplateCtx,
-					WithSidebar:       pg.WithSidebar,
-					OpenGraphImageGen: ogimage,
-				},
-			),
-		)
+		pages := &tasks.Pages{
+			Sources:           src,
+			SourceDir:         pg.SourceDir,
+			Toc:               pg.Toc,
+			ExtraDependencies: pg.ExtraDependencies,
+			PrettyURL:         prettyURL,
+			BaseDestination:   pg.BaseDestination,
+			Template:          pg.Template,
+			TemplateCtx:       pg.TemplateCtx,
+			WithSidebar:       pg.WithSidebar,
+			StateDirectory:    *fStateDir,
+			OpenGraphImageGen: ogimage,
+		}
+		menu.Pages = append(menu.Pages, pages)
+		rv = append(rv, runner.NewTaskGroup(pages))
 	}
 
 	globalPostSources := []*tasks.PostsSources{}
@@ -297,9 +323,11 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 			SourceDir: tasks.PostsSources{
 				Dir:             ps.SourceDir,
 				BaseDestination: ps.BaseDestination,
+				Permalink:       ps.Permalink,
 			},
 			Toc:               ps.Toc,
 			Template:          ps.Template,
+			TemplateSeries:    ps.TemplateSeries,
 			TemplateCtx:       ps.TemplateCtx,
 			WithSidebar:       ps.WithSidebar,
 			OpenGraphImageGen: ogimage,
@@ -307,6 +335,7 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 		postsSources := &tasks.PostsSources{
 			Dir:             ps.SourceDir,
 			BaseDestination: ps.BaseDestination,
+			Permalink:       ps.Permalink,
 		}
 		globalPostSources = append(globalPostSources, postsSources)
 
@@ -342,6 +371,27 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 				},
 			),
 		)
+
+		if ps.Archive {
+			rv = append(rv,
+				runner.NewTaskGroup(
+					&tasks.Archive{
+						Title:           ps.Title,
+						Description:     ps.Description,
+						SourceDirs:      []*tasks.PostsSources{postsSources},
+						SortReverse:     sortReverse,
+						BaseDestination: ps.BaseDestination,
+						Template:        ps.TemplateArchive,
+						TemplateCtx:     ps.TemplateCtx,
+						WithSidebar:     ps.WithSidebar,
+						StateDirectory:  *fStateDir,
+
+						OpenGraph:         ps.OpenGraph,
+						OpenGraphImageGen: ogimage,
+					},
+				),
+			)
+		}
 	}
 
 	sortReverse := true
@@ -381,6 +431,100 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 		),
 	)
 
+	for _, id := range slices.Sorted(maps.Keys(c.Authors)) {
+		author, err := templates.GetAuthor(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// author pages list all the posts by default
+		ppp := c.AuthorPages.PostsPerPage
+		if ppp == 0 {
+			ppp = -1
+		}
+		pppAtom := c.AuthorPages.PostsPerPageAtom
+		if pppAtom == 0 {
+			pppAtom = 10
+		}
+
+		description := author.Bio
+		if description == "" {
+			description = fmt.Sprintf("Posts by %s.", author.Name)
+		}
+
+		rv = append(rv,
+			runner.NewTaskGroup(
+				&tasks.Pagination{
+					Title:           author.Name,
+					Description:     description,
+					SourceDirs:      globalPostSources,
+					PostsPerPage:    ppp,
+					SortReverse:     sortReverse,
+					BaseDestination: c.GetAuthorBaseDestination(id),
+					Template:        c.AuthorPages.Template,
+					TemplateCtx:     c.AuthorPages.TemplateCtx,
+					WithSidebar:     c.AuthorPages.WithSidebar,
+					Author:          author,
+
+					OpenGraph:         c.AuthorPages.OpenGraph,
+					OpenGraphImageGen: ogimage,
+				},
+			),
+			runner.NewTaskGroup(
+				&tasks.Pagination{
+					Title:           author.Name,
+					Description:     description,
+					SourceDirs:      globalPostSources,
+					PostsPerPage:    pppAtom,
+					SortReverse:     true,
+					Atom:            true,
+					BaseDestination: c.GetAuthorBaseDestination(id),
+					Template:        c.AuthorPages.TemplateAtom,
+					TemplateCtx:     c.AuthorPages.TemplateCtx,
+					Author:          author,
+				},
+			),
+		)
+	}
+
+	if c.Posts.Archive {
+		rv = append(rv,
+			runner.NewTaskGroup(
+				&tasks.Archive{
+					Title:           c.Posts.Title,
+					Description:     c.Posts.Description,
+					SourceDirs:      globalPostSources,
+					SortReverse:     sortReverse,
+					BaseDestination: c.Posts.BaseDestination,
+					Template:        c.Posts.TemplateArchive,
+					TemplateCtx:     c.Posts.TemplateCtx,
+					WithSidebar:     c.Posts.WithSidebar,
+					StateDirectory:  *fStateDir,
+
+					OpenGraph:         c.Posts.OpenGraph,
+					OpenGraphImageGen: ogimage,
+				},
+			),
+		)
+	}
+
+	redirects := []*tasks.Redirect{}
+	for _, rd := range c.Redirects {
+		redirects = append(redirects, &tasks.Redirect{
+			From: rd.From,
+			To:   rd.To,
+		})
+	}
+	rv = append(rv,
+		runner.NewTaskGroup(
+			&tasks.Redirects{
+				Redirects: redirects,
+				Pages:     menu.Pages,
+				Posts:     globalPostSources,
+			},
+		),
+	)
+
 	for _, qr := range c.QRCode {
 		rv = append(rv,
 			runner.NewTaskGroup(
@@ -409,7 +553,13 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 	}
 
 	dfuProjects := []string{}
+	overviewGroups := []*tasks.ProjectsOverviewGroup{}
 	for _, pj := range c.Projects {
+		overviewGroup := &tasks.ProjectsOverviewGroup{
+			Title: pj.Title,
+		}
+		overviewGroups = append(overviewGroups, overviewGroup)
+
 		for _, repo := range pj.Repositories {
 			localDir := (*string)(nil)
 			if v, ok := map[string]string(*fLocalDir)[repo.Owner+"/"+repo.Repo]; ok {
@@ -423,6 +573,12 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 				immutable = false
 			}
 
+			// revalidated after the ttl of the group, if any
+			ttl := pj.TTL
+			if repo.TTL != nil {
+				ttl = *repo.TTL
+			}
+
 			licenses := []*project.ProjectLicense{}
 			for _, lic := range repo.Licenses {
 				licenses = append(licenses, &project.ProjectLicense{
@@ -431,29 +587,45 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 				})
 			}
 
+			projBadges := []*project.ProjectBadge{}
+			for _, b := range repo.Badges.Custom {
+				projBadges = append(projBadges, &project.ProjectBadge{
+					Name:  b.Name,
+					Label: b.Label,
+					Value: b.Value,
+					Color: b.Color,
+				})
+			}
+
 			rolling := "rolling"
 			if repo.RollingTag != nil {
 				rolling = *repo.RollingTag
 			}
 
 			proj := &project.Project{
-				Owner:      repo.Owner,
-				Repo:       repo.Repo,
-				Licenses:   licenses,
-				RollingTag: rolling,
+				Owner:        repo.Owner,
+				Repo:         repo.Repo,
+				Subdirectory: repo.Subdirectory,
+				Licenses:     licenses,
+				RollingTag:   rolling,
 
 				Files: repo.Files,
 
-				GoImport: repo.Go.Import,
-				GoRepo:   repo.Go.Repo,
+				GoImport:   repo.Go.Import,
+				GoRepo:     repo.Go.Repo,
+				GoPackages: repo.Go.Packages,
 
-				Toc: repo.Toc,
+				Toc:        repo.Toc,
+				UnsafeHTML: repo.UnsafeHTML,
 
 				Force:             *fForce,
 				LocalDirectory:    localDir,
 				BaseDestination:   pj.BaseDestination,
 				Template:          pj.Template,
 				Immutable:         immutable,
+				TTL:               ttl,
+				StateDirectory:    *fStateDir,
+				BuildDirectory:    *fBuildDir,
 				OpenGraph:         repo.OpenGraph,
 				OpenGraphImageGen: ogimage,
 
@@ -463,14 +635,55 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 				CDocsTemplate:      repo.CDocs.Template,
 				CDocsOpenGraph:     repo.CDocs.OpenGraph,
 
+				GoDocsEnabled:     repo.Go.Docs.Enabled,
+				GoDocsDestination: repo.Go.Docs.Destination,
+				GoDocsTemplate:    repo.Go.Docs.Template,
+				GoDocsInternal:    repo.Go.Docs.Internal,
+				GoDocsGOOS:        repo.Go.Docs.GOOS,
+				GoDocsGOARCH:      repo.Go.Docs.GOARCH,
+				GoDocsOpenGraph:   repo.Go.Docs.OpenGraph,
+
 				DfuDestination:          repo.Dfu.Destination,
 				DfuReleaseAssetsPattern: repo.Dfu.ReleaseAssetsPattern,
+
+				KicadEnabled: len(repo.Kicad.Projects) > 0,
+
+				ReleasesDestination:  repo.Releases.Destination,
+				ReleasesPerPage:      repo.Releases.PerPage,
+				ReleasesPerPageAtom:  repo.Releases.PerPageAtom,
+				ReleasesTemplate:     repo.Releases.Template,
+				ReleasesTemplateAtom: repo.Releases.TemplateAtom,
+				ReleasesOpenGraph:    repo.Releases.OpenGraph,
+
+				SourceDestination: repo.Source.Destination,
+				SourcePatterns:    repo.Source.Patterns,
+				SourceTemplate:    repo.Source.Template,
+				SourceOpenGraph:   repo.Source.OpenGraph,
+
+				IssuesDestination: repo.Issues.Destination,
+				IssuesMilestones:  repo.Issues.Milestones,
+				IssuesLabels:      repo.Issues.Labels,
+				IssuesTemplate:    repo.Issues.Template,
+				IssuesOpenGraph:   repo.Issues.OpenGraph,
+
+				DownloadsEnabled:       repo.Downloads.Enabled,
+				DownloadsDestination:   repo.Downloads.Destination,
+				DownloadsReleases:      repo.Downloads.Releases,
+				DownloadsMirrorPattern: repo.Downloads.MirrorPattern,
+				DownloadsTemplate:      repo.Downloads.Template,
+				DownloadsOpenGraph:     repo.Downloads.OpenGraph,
+
+				BadgesEnabled:     repo.Badges.Enabled,
+				BadgesDestination: repo.Badges.Destination,
+				BadgesCustom:      projBadges,
+				BadgeRenderer:     badges,
 			}
 			rv = append(rv,
 				runner.NewTaskGroup(
 					proj,
 				),
 			)
+			overviewGroup.Projects = append(overviewGroup.Projects, proj)
 
 			if index := proj.GetDfuIndexUrl(); index != "" {
 				dfuProjects = append(dfuProjects, index)
@@ -496,6 +709,41 @@ func getTaskGroups(c *config.Config) ([]*runner.TaskGroup, error) {
 		}
 	}
 
+	// the overview must come after the projects, to reuse their data
+	if c.ProjectsOverview != nil {
+		rv = append(rv, runner.NewTaskGroup(
+			&tasks.ProjectsOverview{
+				Title:             c.ProjectsOverview.Title,
+				Description:       c.ProjectsOverview.Description,
+				Groups:            overviewGroups,
+				Group:             c.ProjectsOverview.Group,
+				Sort:              c.ProjectsOverview.Sort,
+				BaseDestination:   c.ProjectsOverview.BaseDestination,
+				Template:          c.ProjectsOverview.Template,
+				WithSidebar:       c.ProjectsOverview.WithSidebar,
+				OpenGraph:         c.ProjectsOverview.OpenGraph,
+				OpenGraphImageGen: ogimage,
+			},
+		))
+	}
+
+	if c.GoVanity != nil {
+		rv = append(rv, runner.NewTaskGroup(
+			&tasks.GoVanity{
+				Title:             c.GoVanity.Title,
+				Description:       c.GoVanity.Description,
+				SiteURL:           c.URL,
+				BaseDestination:   c.GoVanity.BaseDestination,
+				Template:          c.GoVanity.Template,
+				TemplateImport:    c.GoVanity.TemplateImport,
+				WithSidebar:       c.GoVanity.WithSidebar,
+				Modules:           c.GoVanity.Modules,
+				OpenGraph:         c.GoVanity.OpenGraph,
+				OpenGraphImageGen: ogimage,
+			},
+		))
+	}
+
 	if c.DfuFlasher != nil {
 		rv = append(rv, runner.NewTaskGroup(
 			&tasks.DfuFlasher{
@@ -520,6 +768,9 @@ func build() error {
 		if err != nil {
 			return err
 		}
+		if err := theme.SetDirectories(cfg.GetThemeDirectories()); err != nil {
+			return err
+		}
 		templates.SetConfig(cfg)
 
 		tg, err := getTaskGroups(cfg)
@@ -533,7 +784,15 @@ func build() error {
 		// force only first time
 		force = false
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	// the development server rebuilds continuously, only check regular builds
+	if !*fRunServer {
+		return govanitychecker.Check(cfg, *fBuildDir)
+	}
+	return nil
 }
 
 func buildKicad() error {